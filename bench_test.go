@@ -59,3 +59,152 @@ func BenchmarkAddAll(b *testing.B) {
 		s.AddAll(ints)
 	}
 }
+
+func benchmarkParallel(b *testing.B, add func(int), contains func(int) bool) {
+	ints := randomInts(setSize)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			v := ints[i%len(ints)]
+			if i%4 == 0 {
+				add(v)
+			} else {
+				contains(v)
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkConcurrentSetParallel(b *testing.B) {
+	cs := NewConcurrent[int]()
+	benchmarkParallel(b, cs.Add, cs.Contains)
+}
+
+func BenchmarkShardedSetParallel(b *testing.B) {
+	ss := NewSharded[int](DefaultShards)
+	benchmarkParallel(b, ss.Add, ss.Contains)
+}
+
+const denseDomain = 4096
+
+func randomDense(n int) []int {
+	i := make([]int, n)
+	for ind := range i {
+		i[ind] = rand.Intn(denseDomain)
+	}
+	return i
+}
+
+func toUints(ints []int) []uint {
+	u := make([]uint, len(ints))
+	for i, v := range ints {
+		u[i] = uint(v)
+	}
+	return u
+}
+
+func BenchmarkSetIntersectionDense(b *testing.B) {
+	s1, s2 := NewFromSlice(randomDense(setSize)), NewFromSlice(randomDense(setSize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Intersection(s2)
+	}
+}
+
+func BenchmarkBitSetIntersectionDense(b *testing.B) {
+	s1 := NewBitSetFromSlice(toUints(randomDense(setSize)))
+	s2 := NewBitSetFromSlice(toUints(randomDense(setSize)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Intersection(s2)
+	}
+}
+
+func BenchmarkSetUnionDense(b *testing.B) {
+	s1, s2 := NewFromSlice(randomDense(setSize)), NewFromSlice(randomDense(setSize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Union(s2)
+	}
+}
+
+func BenchmarkBitSetUnionDense(b *testing.B) {
+	s1 := NewBitSetFromSlice(toUints(randomDense(setSize)))
+	s2 := NewBitSetFromSlice(toUints(randomDense(setSize)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Union(s2)
+	}
+}
+
+func BenchmarkSetContainsDense(b *testing.B) {
+	s := NewFromSlice(randomDense(setSize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Contains(i % denseDomain)
+	}
+}
+
+func BenchmarkBitSetContainsDense(b *testing.B) {
+	s := NewBitSetFromSlice(toUints(randomDense(setSize)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Contains(uint(i % denseDomain))
+	}
+}
+
+func randomUint32s(n int, domain uint32) []uint32 {
+	u := make([]uint32, n)
+	for i := range u {
+		u[i] = rand.Uint32() % domain
+	}
+	return u
+}
+
+func BenchmarkSetIntersectionUint32(b *testing.B) {
+	s1 := NewFromSlice(randomUint32s(100*setSize, 1<<20))
+	s2 := NewFromSlice(randomUint32s(100*setSize, 1<<20))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Intersection(s2)
+	}
+}
+
+func BenchmarkRoaringSetIntersection(b *testing.B) {
+	s1 := NewRoaringFromSlice(randomUint32s(100*setSize, 1<<20))
+	s2 := NewRoaringFromSlice(randomUint32s(100*setSize, 1<<20))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Intersection(s2)
+	}
+}
+
+func BenchmarkRoaringSetIntersectionLen(b *testing.B) {
+	s1 := NewRoaringFromSlice(randomUint32s(100*setSize, 1<<20))
+	s2 := NewRoaringFromSlice(randomUint32s(100*setSize, 1<<20))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.IntersectionLen(s2)
+	}
+}
+
+func BenchmarkSetJaccard(b *testing.B) {
+	s1 := NewFromSlice(randomInts(setSize))
+	s2 := NewFromSlice(randomInts(setSize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s1.Jaccard(s2)
+	}
+}
+
+func BenchmarkSignatureSimilarity(b *testing.B) {
+	mh := NewMinHash[int](DefaultMinHashPermutations)
+	sig1 := mh.Signature(NewFromSlice(randomInts(setSize)))
+	sig2 := mh.Signature(NewFromSlice(randomInts(setSize)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sig1.Similarity(sig2)
+	}
+}
@@ -0,0 +1,229 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// binaryVersion is the first byte of every binary encoded Set.
+const binaryVersion = 1
+
+// Element encodings, stored in the second byte of a binary encoded Set.
+const (
+	binarySigned   = 1 // zigzag varint first element, uvarint deltas
+	binaryUnsigned = 2 // uvarint first element, uvarint deltas
+	binaryString   = 3 // uvarint length prefixed strings
+	binaryGob      = 4 // gob encoded slice of elements
+)
+
+// ErrInvalidEncoding is returned when decoding malformed binary data.
+var ErrInvalidEncoding = errors.New("set: invalid binary encoding")
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding starts with
+// a version byte and an element encoding byte. Integer elements are sorted and
+// delta encoded as varints, strings are length prefixed and any other type is
+// gob encoded.
+func (set Set[T]) MarshalBinary() ([]byte, error) {
+	kind := binaryKind[T]()
+	buf := []byte{binaryVersion, kind}
+	if kind == binaryGob {
+		var b bytes.Buffer
+		if err := gob.NewEncoder(&b).Encode(set.ToSlice()); err != nil {
+			return nil, err
+		}
+		return append(buf, b.Bytes()...), nil
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(set)))
+	switch kind {
+	case binarySigned:
+		values := make([]int64, 0, len(set))
+		for s := range set {
+			values = append(values, reflect.ValueOf(s).Int())
+		}
+		slices.Sort(values)
+		for i, v := range values {
+			if i == 0 {
+				buf = binary.AppendVarint(buf, v)
+			} else {
+				buf = binary.AppendUvarint(buf, uint64(v)-uint64(values[i-1]))
+			}
+		}
+	case binaryUnsigned:
+		values := make([]uint64, 0, len(set))
+		for s := range set {
+			values = append(values, reflect.ValueOf(s).Uint())
+		}
+		slices.Sort(values)
+		prev := uint64(0)
+		for _, v := range values {
+			buf = binary.AppendUvarint(buf, v-prev)
+			prev = v
+		}
+	case binaryString:
+		values := make([]string, 0, len(set))
+		for s := range set {
+			values = append(values, reflect.ValueOf(s).String())
+		}
+		slices.Sort(values)
+		for _, v := range values {
+			buf = binary.AppendUvarint(buf, uint64(len(v)))
+			buf = append(buf, v...)
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It replaces the
+// content of a Set with the decoded elements.
+func (set *Set[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < 2 {
+		return fmt.Errorf("%w: data too short", ErrInvalidEncoding)
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, data[0])
+	}
+	kind := binaryKind[T]()
+	if data[1] != kind {
+		return fmt.Errorf("%w: element encoding %d does not match type %T", ErrInvalidEncoding, data[1], *new(T))
+	}
+	data = data[2:]
+
+	if kind == binaryGob {
+		var slice []T
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&slice); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+		}
+		*set = NewFromSlice(slice)
+		return nil
+	}
+
+	n, err := readUvarint(&data)
+	if err != nil {
+		return err
+	}
+	// every element takes at least one byte, this bounds the allocation
+	if n > uint64(len(data)) {
+		return fmt.Errorf("%w: %d elements do not fit in %d bytes", ErrInvalidEncoding, n, len(data))
+	}
+	result := make(Set[T], n)
+	var prev uint64
+	for i := uint64(0); i < n; i++ {
+		var s T
+		v := reflect.ValueOf(&s).Elem()
+		switch kind {
+		case binarySigned:
+			var x int64
+			if i == 0 {
+				var k int
+				x, k = binary.Varint(data)
+				if k <= 0 {
+					return fmt.Errorf("%w: bad varint", ErrInvalidEncoding)
+				}
+				data = data[k:]
+			} else {
+				delta, err := readUvarint(&data)
+				if err != nil {
+					return err
+				}
+				x = int64(prev + delta)
+			}
+			if v.OverflowInt(x) {
+				return fmt.Errorf("%w: %d overflows %T", ErrInvalidEncoding, x, s)
+			}
+			v.SetInt(x)
+			prev = uint64(x)
+		case binaryUnsigned:
+			delta, err := readUvarint(&data)
+			if err != nil {
+				return err
+			}
+			x := prev + delta
+			if v.OverflowUint(x) {
+				return fmt.Errorf("%w: %d overflows %T", ErrInvalidEncoding, x, s)
+			}
+			v.SetUint(x)
+			prev = x
+		case binaryString:
+			l, err := readUvarint(&data)
+			if err != nil {
+				return err
+			}
+			if l > uint64(len(data)) {
+				return fmt.Errorf("%w: string length %d exceeds data", ErrInvalidEncoding, l)
+			}
+			v.SetString(string(data[:l]))
+			data = data[l:]
+		}
+		result[s] = struct{}{}
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidEncoding, len(data))
+	}
+	*set = result
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder using the MarshalBinary format.
+func (set Set[T]) GobEncode() ([]byte, error) {
+	return set.MarshalBinary()
+}
+
+// GobDecode implements gob.GobDecoder using the MarshalBinary format.
+func (set *Set[T]) GobDecode(data []byte) error {
+	return set.UnmarshalBinary(data)
+}
+
+// binaryKind returns the element encoding used for type T.
+func binaryKind[T any]() byte {
+	var zero T
+	switch reflect.ValueOf(&zero).Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binarySigned
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binaryUnsigned
+	case reflect.String:
+		return binaryString
+	default:
+		return binaryGob
+	}
+}
+
+func readUvarint(data *[]byte) (uint64, error) {
+	x, k := binary.Uvarint(*data)
+	if k <= 0 {
+		return 0, fmt.Errorf("%w: bad varint", ErrInvalidEncoding)
+	}
+	*data = (*data)[k:]
+	return x, nil
+}
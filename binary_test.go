@@ -0,0 +1,182 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = Set[int]{}
+	_ encoding.BinaryUnmarshaler = &Set[int]{}
+	_ gob.GobEncoder             = Set[int]{}
+	_ gob.GobDecoder             = &Set[int]{}
+)
+
+func TestSetMarshalBinary(t *testing.T) {
+	cases := []struct {
+		name     string
+		set      encoding.BinaryMarshaler
+		expected []byte
+	}{
+		{name: "empty ints", set: Set[int]{}, expected: []byte{1, 1, 0}},
+		{name: "ints", set: NewFromSlice([]int{-1, 300, 1}), expected: []byte{1, 1, 3, 1, 2, 171, 2}},
+		{name: "uints", set: NewFromSlice([]uint16{5, 1, 3}), expected: []byte{1, 2, 3, 1, 2, 2}},
+		{name: "strings", set: NewFromSlice([]string{"b", "", "ab"}), expected: []byte{1, 3, 3, 0, 2, 'a', 'b', 1, 'b'}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := c.set.MarshalBinary()
+			require.NoError(t, err)
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestSetBinaryRoundTrip(t *testing.T) {
+	t.Run("ints", func(t *testing.T) {
+		testBinaryRoundTrip(t, NewFromSlice([]int64{math.MinInt64, -1, 0, 1, math.MaxInt64}))
+	})
+	t.Run("small ints", func(t *testing.T) {
+		testBinaryRoundTrip(t, NewFromSlice([]int8{math.MinInt8, 0, math.MaxInt8}))
+	})
+	t.Run("uints", func(t *testing.T) {
+		testBinaryRoundTrip(t, NewFromSlice([]uint64{0, 1, math.MaxUint64}))
+	})
+	t.Run("random ints", func(t *testing.T) {
+		testBinaryRoundTrip(t, NewFromSlice(randomInts(setSize)))
+	})
+	t.Run("strings", func(t *testing.T) {
+		testBinaryRoundTrip(t, NewFromSlice([]string{"", "a", "hello world", "ünïcode"}))
+	})
+	t.Run("structs", func(t *testing.T) {
+		testBinaryRoundTrip(t, NewFromSlice([]jsonPoint{{1, 2, "a"}, {3, 4, "b"}}))
+	})
+	t.Run("floats", func(t *testing.T) {
+		testBinaryRoundTrip(t, NewFromSlice([]float64{-1.5, 0, 3.25}))
+	})
+	t.Run("empty", func(t *testing.T) {
+		testBinaryRoundTrip(t, New[string]())
+	})
+}
+
+func testBinaryRoundTrip[T comparable](t *testing.T, set Set[T]) {
+	data, err := set.MarshalBinary()
+	require.NoError(t, err)
+	var actual Set[T]
+	require.NoError(t, actual.UnmarshalBinary(data))
+	require.Equal(t, set, actual)
+
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(set))
+	var decoded Set[T]
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&decoded))
+	require.Equal(t, set, decoded)
+}
+
+func TestSetGobStructField(t *testing.T) {
+	type cached struct {
+		Name string
+		IDs  Set[uint32]
+	}
+	in := cached{Name: "ids", IDs: NewFromSlice([]uint32{7, 3, 5})}
+	var buf bytes.Buffer
+	require.NoError(t, gob.NewEncoder(&buf).Encode(in))
+	var out cached
+	require.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	require.Equal(t, in, out)
+}
+
+func TestSetUnmarshalBinaryErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "short", data: []byte{1}},
+		{name: "bad version", data: []byte{2, 1, 0}},
+		{name: "wrong element encoding", data: []byte{1, 3, 0}},
+		{name: "missing count", data: []byte{1, 1}},
+		{name: "count too large", data: []byte{1, 1, 5, 1}},
+		{name: "truncated varint", data: []byte{1, 1, 1, 0x80}},
+		{name: "trailing bytes", data: []byte{1, 1, 1, 2, 3}},
+		{name: "overflow", data: []byte{1, 1, 1, 0x80, 0x04}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var set Set[int8]
+			require.ErrorIs(t, set.UnmarshalBinary(c.data), ErrInvalidEncoding)
+		})
+	}
+
+	var strings Set[string]
+	require.ErrorIs(t, strings.UnmarshalBinary([]byte{1, 3, 1, 5, 'a'}), ErrInvalidEncoding)
+	var unsigned Set[uint8]
+	require.ErrorIs(t, unsigned.UnmarshalBinary([]byte{1, 2, 1, 0x80, 0x04}), ErrInvalidEncoding)
+	var structs Set[jsonPoint]
+	require.ErrorIs(t, structs.UnmarshalBinary([]byte{1, 4, 0xff}), ErrInvalidEncoding)
+}
+
+func FuzzSetUnmarshalBinary(f *testing.F) {
+	for _, seed := range []encoding.BinaryMarshaler{
+		NewFromSlice([]int{-5, 0, 42}),
+		NewFromSlice([]int8{-1, 1}),
+		NewFromSlice([]uint32{1, 2, 1 << 31}),
+		NewFromSlice([]string{"a", "bc"}),
+		NewFromSlice([]jsonPoint{{1, 2, "a"}}),
+	} {
+		data, err := seed.MarshalBinary()
+		require.NoError(f, err)
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzBinaryDecode[int](t, data)
+		fuzzBinaryDecode[int8](t, data)
+		fuzzBinaryDecode[uint32](t, data)
+		fuzzBinaryDecode[string](t, data)
+		fuzzBinaryDecode[jsonPoint](t, data)
+	})
+}
+
+// fuzzBinaryDecode decodes arbitrary data and, when it is accepted, checks
+// that encoding it again gives back the same Set.
+func fuzzBinaryDecode[T comparable](t *testing.T, data []byte) {
+	var set Set[T]
+	if err := set.UnmarshalBinary(data); err != nil {
+		return
+	}
+	encoded, err := set.MarshalBinary()
+	require.NoError(t, err)
+	var again Set[T]
+	require.NoError(t, again.UnmarshalBinary(encoded))
+	require.Equal(t, set, again)
+}
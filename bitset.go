@@ -0,0 +1,254 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// BitSet is a not threadsafe set of small non-negative integers, stored as a
+// bitmap. It uses one bit per possible element up to the largest one, so it
+// is much smaller and faster than a Set[uint] for dense domains like flags,
+// shard IDs or enum values. The zero value is an empty BitSet ready to use.
+//
+// Memory is proportional to the largest element ever added, not to the
+// number of elements: a BitSet holding only 1<<30 takes 128 MiB and one
+// holding 1<<40 asks for 128 GiB. Running out of memory is a fatal error that
+// recover cannot catch, so check elements from untrusted input against a
+// bound first. Use a RoaringSet or a Set[uint] for sparse or unbounded
+// values.
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet creates a new BitSet.
+func NewBitSet() *BitSet {
+	return &BitSet{}
+}
+
+// NewBitSetFromSlice creates a new BitSet from a slice of elements. It takes
+// s/8 bytes for the largest element s, see BitSet.
+func NewBitSetFromSlice(slice []uint) *BitSet {
+	b := NewBitSet()
+	b.AddAll(slice)
+	return b
+}
+
+// NewBitSetFromSet creates a new BitSet from the elements of a Set. It takes
+// s/8 bytes for the largest element s, see BitSet.
+func NewBitSetFromSet(set Set[uint]) *BitSet {
+	b := NewBitSet()
+	for s := range set {
+		b.Add(s)
+	}
+	return b
+}
+
+// Len returns the number of elements in a BitSet.
+func (b *BitSet) Len() int {
+	n := 0
+	for _, w := range b.words {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// ToSlice returns an ascending slice of elements from a BitSet.
+func (b *BitSet) ToSlice() []uint {
+	slice := make([]uint, 0, b.Len())
+	for s := range b.All() {
+		slice = append(slice, s)
+	}
+	return slice
+}
+
+// ToSet returns the elements of a BitSet as a Set.
+func (b *BitSet) ToSet() Set[uint] {
+	set := make(Set[uint], b.Len())
+	for s := range b.All() {
+		set[s] = struct{}{}
+	}
+	return set
+}
+
+// All returns an iterator over the elements of a BitSet in ascending order.
+func (b *BitSet) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				s := uint(i)*64 + uint(bits.TrailingZeros64(w))
+				if !yield(s) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// NextSet returns the smallest element greater than or equal to i, or false
+// if there is none. Use it to iterate a BitSet:
+//
+//	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+//		...
+//	}
+func (b *BitSet) NextSet(i uint) (uint, bool) {
+	w := i / 64
+	if w >= uint(len(b.words)) {
+		return 0, false
+	}
+	word := b.words[w] >> (i % 64)
+	if word != 0 {
+		return i + uint(bits.TrailingZeros64(word)), true
+	}
+	for w++; w < uint(len(b.words)); w++ {
+		if b.words[w] != 0 {
+			return w*64 + uint(bits.TrailingZeros64(b.words[w])), true
+		}
+	}
+	return 0, false
+}
+
+// Contains returns true if a BitSet contains an element.
+func (b *BitSet) Contains(s uint) bool {
+	w := s / 64
+	return w < uint(len(b.words)) && b.words[w]&(1<<(s%64)) != 0
+}
+
+// Add adds an element to a BitSet, growing it to s/8 bytes if smaller. If
+// that memory cannot be allocated the program dies, so s must come from a
+// bounded domain.
+func (b *BitSet) Add(s uint) {
+	w := s / 64
+	if w >= uint(len(b.words)) {
+		b.words = append(b.words, make([]uint64, w+1-uint(len(b.words)))...)
+	}
+	b.words[w] |= 1 << (s % 64)
+}
+
+// Remove removes an element from a BitSet.
+func (b *BitSet) Remove(s uint) {
+	w := s / 64
+	if w < uint(len(b.words)) {
+		b.words[w] &^= 1 << (s % 64)
+	}
+}
+
+// AddAll adds a slice of elements to a BitSet, growing it as Add does.
+func (b *BitSet) AddAll(slice []uint) {
+	for _, s := range slice {
+		b.Add(s)
+	}
+}
+
+// RemoveAll removes a slice of elements from a BitSet.
+func (b *BitSet) RemoveAll(slice []uint) {
+	for _, s := range slice {
+		b.Remove(s)
+	}
+}
+
+// Equals returns true if two BitSets are equal.
+func (b *BitSet) Equals(other *BitSet) bool {
+	short, long := b.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	for i, w := range short {
+		if w != long[i] {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubsetOf returns true if a BitSet is a subset of another BitSet (they can
+// be equal).
+func (b *BitSet) IsSubsetOf(other *BitSet) bool {
+	for i, w := range b.words {
+		var o uint64
+		if i < len(other.words) {
+			o = other.words[i]
+		}
+		if w&^o != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProperSubsetOf returns true if a BitSet is a proper subset of another
+// BitSet (they cannot be equal).
+func (b *BitSet) IsProperSubsetOf(other *BitSet) bool {
+	return b.IsSubsetOf(other) && !b.Equals(other)
+}
+
+// Union returns the union of two BitSets as new BitSet.
+func (b *BitSet) Union(other *BitSet) *BitSet {
+	short, long := b.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	words := make([]uint64, len(long))
+	copy(words, long)
+	for i, w := range short {
+		words[i] |= w
+	}
+	return &BitSet{words: words}
+}
+
+// Intersection returns the intersection of two BitSets as new BitSet.
+func (b *BitSet) Intersection(other *BitSet) *BitSet {
+	words := make([]uint64, min(len(b.words), len(other.words)))
+	for i := range words {
+		words[i] = b.words[i] & other.words[i]
+	}
+	return &BitSet{words: trimWords(words)}
+}
+
+// Difference returns the difference of two BitSets as new BitSet.
+func (b *BitSet) Difference(other *BitSet) *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	for i := range min(len(words), len(other.words)) {
+		words[i] &^= other.words[i]
+	}
+	return &BitSet{words: trimWords(words)}
+}
+
+// trimWords drops trailing zero words.
+func trimWords(words []uint64) []uint64 {
+	for len(words) > 0 && words[len(words)-1] == 0 {
+		words = words[:len(words)-1]
+	}
+	return words
+}
@@ -0,0 +1,199 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBitSetBasic(t *testing.T) {
+	var b BitSet
+	require.Equal(t, 0, b.Len())
+	require.False(t, b.Contains(0))
+	require.Equal(t, []uint{}, b.ToSlice())
+
+	b.AddAll([]uint{3, 0, 64, 200, 3})
+	require.Equal(t, 4, b.Len())
+	require.Equal(t, []uint{0, 3, 64, 200}, b.ToSlice())
+	require.True(t, b.Contains(64))
+	require.False(t, b.Contains(65))
+	require.False(t, b.Contains(10000))
+
+	b.Remove(64)
+	b.Remove(10000)
+	b.RemoveAll([]uint{0, 1})
+	require.Equal(t, []uint{3, 200}, b.ToSlice())
+}
+
+func TestBitSetNextSet(t *testing.T) {
+	b := NewBitSetFromSlice([]uint{1, 63, 64, 130})
+	cases := []struct {
+		from     uint
+		expected uint
+		ok       bool
+	}{
+		{from: 0, expected: 1, ok: true},
+		{from: 1, expected: 1, ok: true},
+		{from: 2, expected: 63, ok: true},
+		{from: 64, expected: 64, ok: true},
+		{from: 65, expected: 130, ok: true},
+		{from: 131, expected: 0, ok: false},
+		{from: 1000, expected: 0, ok: false},
+	}
+	for _, c := range cases {
+		actual, ok := b.NextSet(c.from)
+		require.Equal(t, c.ok, ok, "from %d", c.from)
+		require.Equal(t, c.expected, actual, "from %d", c.from)
+	}
+
+	seen := make([]uint, 0)
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		seen = append(seen, i)
+	}
+	require.Equal(t, b.ToSlice(), seen)
+	require.Equal(t, []uint{1, 63}, firstN(b.All(), 2))
+}
+
+func TestBitSetConversion(t *testing.T) {
+	set := NewFromSlice([]uint{5, 70, 1})
+	b := NewBitSetFromSet(set)
+	require.Equal(t, []uint{1, 5, 70}, b.ToSlice())
+	require.Equal(t, set, b.ToSet())
+}
+
+func TestBitSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          []uint
+		other        []uint
+		union        []uint
+		intersection []uint
+		difference   []uint
+		equals       bool
+		subset       bool
+		properSubset bool
+	}{
+		{
+			name:         "empty sets",
+			set:          []uint{},
+			other:        []uint{},
+			union:        []uint{},
+			intersection: []uint{},
+			difference:   []uint{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "equal sets",
+			set:          []uint{1, 100},
+			other:        []uint{100, 1},
+			union:        []uint{1, 100},
+			intersection: []uint{1, 100},
+			difference:   []uint{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "proper subset with longer other",
+			set:          []uint{1},
+			other:        []uint{1, 300},
+			union:        []uint{1, 300},
+			intersection: []uint{1},
+			difference:   []uint{},
+			equals:       false,
+			subset:       true,
+			properSubset: true,
+		},
+		{
+			name:         "partial overlap",
+			set:          []uint{1, 2, 200},
+			other:        []uint{2, 3},
+			union:        []uint{1, 2, 3, 200},
+			intersection: []uint{2},
+			difference:   []uint{1, 200},
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+		{
+			name:         "disjoint high bits",
+			set:          []uint{500},
+			other:        []uint{1},
+			union:        []uint{1, 500},
+			intersection: []uint{},
+			difference:   []uint{500},
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := NewBitSetFromSlice(c.set)
+			other := NewBitSetFromSlice(c.other)
+			require.Equal(t, c.union, b.Union(other).ToSlice())
+			require.Equal(t, c.intersection, b.Intersection(other).ToSlice())
+			require.Equal(t, c.difference, b.Difference(other).ToSlice())
+			require.Equal(t, c.equals, b.Equals(other))
+			require.Equal(t, c.subset, b.IsSubsetOf(other))
+			require.Equal(t, c.properSubset, b.IsProperSubsetOf(other))
+		})
+	}
+}
+
+func TestBitSetEqualsAfterRemove(t *testing.T) {
+	b := NewBitSetFromSlice([]uint{1, 1000})
+	b.Remove(1000)
+	require.True(t, b.Equals(NewBitSetFromSlice([]uint{1})))
+	require.True(t, NewBitSetFromSlice([]uint{1}).Equals(b))
+	require.True(t, b.IsSubsetOf(NewBitSetFromSlice([]uint{1})))
+}
+
+func TestBitSetMatchesSet(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randomSet := func() Set[uint] {
+		set := New[uint]()
+		for i := 0; i < 200; i++ {
+			set.Add(uint(rnd.Intn(1000)))
+		}
+		return set
+	}
+	for i := 0; i < 20; i++ {
+		a, b := randomSet(), randomSet()
+		ba, bb := NewBitSetFromSet(a), NewBitSetFromSet(b)
+		require.Equal(t, a.Union(b), ba.Union(bb).ToSet())
+		require.Equal(t, a.Intersection(b), ba.Intersection(bb).ToSet())
+		require.Equal(t, a.Difference(b), ba.Difference(bb).ToSet())
+		require.Equal(t, len(a), ba.Len())
+		require.True(t, slices.IsSorted(ba.ToSlice()))
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// DefaultBloomFPRate is the false positive rate used by NewBloom when the
+// requested one is not between 0 and 1.
+const DefaultBloomFPRate = 0.01
+
+// maxBloomHashes bounds the number of bits set per element. 64 hashes already
+// give a false positive rate of 2⁻⁶⁴ at the best load.
+const maxBloomHashes = 64
+
+// bloomVersion is the first byte of every binary encoded BloomFilter.
+const bloomVersion = 1
+
+// ErrIncompatible is returned when combining probabilistic structures built
+// with different parameters.
+var ErrIncompatible = errors.New("set: incompatible parameters")
+
+// BloomFilter is a generic, not threadsafe probabilistic set. It never
+// reports an added element as absent, but may report an element that was
+// never added as present, at a rate chosen when sizing it. Elements cannot be
+// removed or listed.
+type BloomFilter[T comparable] struct {
+	words  []uint64
+	m      uint64 // number of bits
+	hashes uint32 // number of bits set per element
+}
+
+// NewBloom creates a new BloomFilter sized to hold n elements with a false
+// positive rate of fpRate. Holding more elements raises the rate. It sets at
+// most 64 bits per element, however low fpRate is.
+func NewBloom[T comparable](n int, fpRate float64) *BloomFilter[T] {
+	if n < 1 {
+		n = 1
+	}
+	if !(fpRate > 0 && fpRate < 1) {
+		fpRate = DefaultBloomFPRate
+	}
+	m := math.Ceil(-float64(n) * math.Log(fpRate) / (math.Ln2 * math.Ln2))
+	k := math.Round(m / float64(n) * math.Ln2)
+	return newBloom[T](uint64(m), uint32(min(max(k, 1), maxBloomHashes)))
+}
+
+// NewBloomFromSet creates a new BloomFilter sized for the elements of a Set
+// with a false positive rate of fpRate, and adds them.
+func NewBloomFromSet[T comparable](set Set[T], fpRate float64) *BloomFilter[T] {
+	bf := NewBloom[T](len(set), fpRate)
+	for s := range set {
+		bf.Add(s)
+	}
+	return bf
+}
+
+func newBloom[T comparable](m uint64, hashes uint32) *BloomFilter[T] {
+	return &BloomFilter[T]{words: make([]uint64, (m+63)/64), m: m, hashes: hashes}
+}
+
+// BitLen returns the number of bits of a BloomFilter.
+func (bf *BloomFilter[T]) BitLen() int {
+	return int(bf.m)
+}
+
+// HashCount returns the number of bits set for every element of a
+// BloomFilter.
+func (bf *BloomFilter[T]) HashCount() int {
+	return int(bf.hashes)
+}
+
+// Add adds an element to a BloomFilter.
+func (bf *BloomFilter[T]) Add(s T) {
+	h1, h2 := stableHash(s)
+	for i := uint64(0); i < uint64(bf.hashes); i++ {
+		bit := (h1 + i*h2) % bf.m
+		bf.words[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+// AddAll adds a slice of elements to a BloomFilter.
+func (bf *BloomFilter[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		bf.Add(s)
+	}
+}
+
+// MayContain returns false if an element was certainly not added to a
+// BloomFilter, and true if it probably was.
+func (bf *BloomFilter[T]) MayContain(s T) bool {
+	h1, h2 := stableHash(s)
+	for i := uint64(0); i < uint64(bf.hashes); i++ {
+		bit := (h1 + i*h2) % bf.m
+		if bf.words[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimatedLen returns an estimate of the number of distinct elements added
+// to a BloomFilter, from the fraction of bits set.
+func (bf *BloomFilter[T]) EstimatedLen() int {
+	set := 0
+	for _, w := range bf.words {
+		set += bits.OnesCount64(w)
+	}
+	if uint64(set) == bf.m {
+		// saturated, the estimate is unbounded
+		return math.MaxInt
+	}
+	m, k := float64(bf.m), float64(bf.hashes)
+	return int(math.Round(-m / k * math.Log(1-float64(set)/m)))
+}
+
+// Union returns the union of two BloomFilters as new BloomFilter, which
+// reports every element added to either. It returns ErrIncompatible if they
+// differ in size or hash count.
+func (bf *BloomFilter[T]) Union(other *BloomFilter[T]) (*BloomFilter[T], error) {
+	if bf.m != other.m || bf.hashes != other.hashes {
+		return nil, fmt.Errorf("%w: bloom filters of %d bits and %d hashes, %d bits and %d hashes",
+			ErrIncompatible, bf.m, bf.hashes, other.m, other.hashes)
+	}
+	result := newBloom[T](bf.m, bf.hashes)
+	for i := range result.words {
+		result.words[i] = bf.words[i] | other.words[i]
+	}
+	return result, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding holds a
+// version byte, the number of bits and hashes as uvarints and the bits as
+// little endian words.
+func (bf *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 1+2*binary.MaxVarintLen64+8*len(bf.words))
+	buf = append(buf, bloomVersion)
+	buf = binary.AppendUvarint(buf, bf.m)
+	buf = binary.AppendUvarint(buf, uint64(bf.hashes))
+	for _, w := range bf.words {
+		buf = binary.LittleEndian.AppendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the
+// content of a BloomFilter.
+func (bf *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: data too short", ErrInvalidEncoding)
+	}
+	if data[0] != bloomVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, data[0])
+	}
+	data = data[1:]
+	m, err := readUvarint(&data)
+	if err != nil {
+		return err
+	}
+	hashes, err := readUvarint(&data)
+	if err != nil {
+		return err
+	}
+	if m == 0 || hashes == 0 || hashes > maxBloomHashes {
+		return fmt.Errorf("%w: bloom filter of %d bits and %d hashes", ErrInvalidEncoding, m, hashes)
+	}
+	if uint64(len(data))%8 != 0 || uint64(len(data))/8 != (m-1)/64+1 {
+		return fmt.Errorf("%w: %d bits do not fit in %d bytes", ErrInvalidEncoding, m, len(data))
+	}
+	words := make([]uint64, len(data)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	if m%64 != 0 && words[len(words)-1]>>(m%64) != 0 {
+		return fmt.Errorf("%w: bits set past %d", ErrInvalidEncoding, m)
+	}
+	*bf = BloomFilter[T]{words: words, m: m, hashes: uint32(hashes)}
+	return nil
+}
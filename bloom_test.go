@@ -0,0 +1,171 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBloomFilterSizing(t *testing.T) {
+	bf := NewBloom[string](1000, 0.01)
+	require.Equal(t, 9586, bf.BitLen())
+	require.Equal(t, 7, bf.HashCount())
+
+	require.Equal(t, NewBloom[string](1, DefaultBloomFPRate).BitLen(), NewBloom[string](0, 0).BitLen())
+	require.Equal(t, NewBloom[string](10, DefaultBloomFPRate).BitLen(), NewBloom[string](10, 1.5).BitLen())
+	require.GreaterOrEqual(t, NewBloom[string](1, 0.9).HashCount(), 1)
+	require.Equal(t, maxBloomHashes, NewBloom[string](10, 1e-300).HashCount())
+}
+
+func TestBloomFilterNoFalseNegatives(t *testing.T) {
+	bf := NewBloom[int](500, 0.001)
+	values := randomInts(500)
+	bf.AddAll(values)
+	for _, v := range values {
+		require.True(t, bf.MayContain(v))
+	}
+
+	points := NewBloom[jsonPoint](10, 0.01)
+	points.Add(jsonPoint{1, 2, "a"})
+	require.True(t, points.MayContain(jsonPoint{1, 2, "a"}))
+
+	floats := NewBloom[float64](10, 0.01)
+	floats.Add(0)
+	zero := 0.0
+	require.True(t, floats.MayContain(-zero))
+}
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	for _, fpRate := range []float64{0.1, 0.01, 0.001} {
+		t.Run(fmt.Sprint(fpRate), func(t *testing.T) {
+			const n, probes = 10000, 100000
+			bf := NewBloom[string](n, fpRate)
+			for i := 0; i < n; i++ {
+				bf.Add("member-" + strconv.Itoa(i))
+			}
+			positives := 0
+			for i := 0; i < probes; i++ {
+				if bf.MayContain("other-" + strconv.Itoa(i)) {
+					positives++
+				}
+			}
+			actual := float64(positives) / probes
+			require.InDelta(t, fpRate, actual, fpRate*0.3, "false positive rate %v", actual)
+		})
+	}
+}
+
+func TestBloomFilterEstimatedLen(t *testing.T) {
+	bf := NewBloom[int](10000, 0.01)
+	require.Equal(t, 0, bf.EstimatedLen())
+	for _, n := range []int{10, 1000, 10000} {
+		for i := 0; i < n; i++ {
+			bf.Add(i)
+		}
+		require.InEpsilon(t, n, bf.EstimatedLen(), 0.05)
+	}
+
+	full := NewBloom[int](1, 0.5)
+	full.AddAll(randomInts(100))
+	require.Greater(t, full.EstimatedLen(), 100)
+}
+
+func TestBloomFilterUnion(t *testing.T) {
+	a := NewBloomFromSet(NewFromSlice([]string{"a", "b"}), 0.01)
+	b := NewBloom[string](2, 0.01)
+	b.AddAll([]string{"c", "d"})
+	union, err := a.Union(b)
+	require.NoError(t, err)
+	for _, s := range []string{"a", "b", "c", "d"} {
+		require.True(t, union.MayContain(s))
+	}
+	require.False(t, a.MayContain("c") && a.MayContain("d"))
+
+	_, err = a.Union(NewBloom[string](3, 0.01))
+	require.ErrorIs(t, err, ErrIncompatible)
+}
+
+func TestBloomFilterBinary(t *testing.T) {
+	bf := NewBloom[string](100, 0.01)
+	for i := 0; i < 100; i++ {
+		bf.Add(strconv.Itoa(i))
+	}
+	data, err := bf.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded BloomFilter[string]
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, bf, &decoded)
+	for i := 0; i < 100; i++ {
+		require.True(t, decoded.MayContain(strconv.Itoa(i)))
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "bad version", data: []byte{2, 64, 1, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "truncated header", data: []byte{1, 64}},
+		{name: "no bits", data: []byte{1, 0, 1}},
+		{name: "no hashes", data: []byte{1, 64, 0, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "too many hashes", data: []byte{1, 64, maxBloomHashes + 1, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "huge hashes", data: []byte{1, 64, 0xff, 0xff, 0xff, 0xff, 0x0f, 0, 0, 0, 0, 0, 0, 0, 0}},
+		{name: "truncated words", data: []byte{1, 64, 1, 0, 0, 0, 0}},
+		{name: "extra words", data: append([]byte{1, 64, 1}, make([]byte, 16)...)},
+		{name: "bits past size", data: []byte{1, 4, 1, 0x10, 0, 0, 0, 0, 0, 0, 0}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var bf BloomFilter[string]
+			require.ErrorIs(t, bf.UnmarshalBinary(c.data), ErrInvalidEncoding)
+		})
+	}
+}
+
+func FuzzBloomFilterUnmarshalBinary(f *testing.F) {
+	bf := NewBloom[int](50, 0.05)
+	bf.AddAll(randomInts(50))
+	data, err := bf.MarshalBinary()
+	require.NoError(f, err)
+	f.Add(data)
+	f.Add([]byte{1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var bf BloomFilter[int]
+		if err := bf.UnmarshalBinary(data); err != nil {
+			return
+		}
+		encoded, err := bf.MarshalBinary()
+		require.NoError(t, err)
+		var decoded BloomFilter[int]
+		require.NoError(t, decoded.UnmarshalBinary(encoded))
+		require.Equal(t, bf, decoded)
+	})
+}
@@ -0,0 +1,370 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"iter"
+	"slices"
+)
+
+// LRUSet is a generic, not threadsafe set data structure holding at most a
+// fixed number of elements. When full, adding an element evicts the least
+// recently used one; both Add and Contains count as a use.
+type LRUSet[T comparable] struct {
+	order    *OrderedSet[T]
+	capacity int
+	onEvict  func(T)
+}
+
+// NewLRU creates a new LRUSet holding at most capacity elements. A capacity
+// lower than one is treated as one.
+func NewLRU[T comparable](capacity int) *LRUSet[T] {
+	return &LRUSet[T]{order: NewOrdered[T](), capacity: max(capacity, 1)}
+}
+
+// OnEvict registers a callback called with every element evicted to make
+// room for a new one. It is not called for elements removed with Remove.
+func (lru *LRUSet[T]) OnEvict(fn func(T)) {
+	lru.onEvict = fn
+}
+
+// Len returns the number of elements in an LRUSet.
+func (lru *LRUSet[T]) Len() int {
+	return lru.order.Len()
+}
+
+// Cap returns the maximum number of elements in an LRUSet.
+func (lru *LRUSet[T]) Cap() int {
+	return lru.capacity
+}
+
+// ToSlice returns a slice of elements from an LRUSet, from the least to the
+// most recently used.
+func (lru *LRUSet[T]) ToSlice() []T {
+	return lru.order.ToSlice()
+}
+
+// All returns an iterator over the elements of an LRUSet, from the least to
+// the most recently used. Iterating does not count as a use. It iterates a
+// snapshot, so the LRUSet may be used while iterating.
+func (lru *LRUSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, s := range lru.order.ToSlice() {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if an LRUSet contains an element, marking it as the
+// most recently used.
+func (lru *LRUSet[T]) Contains(s T) bool {
+	return lru.order.MoveToEnd(s)
+}
+
+// Peek returns true if an LRUSet contains an element, without marking it as
+// used.
+func (lru *LRUSet[T]) Peek(s T) bool {
+	return lru.order.Contains(s)
+}
+
+// Add adds an element to an LRUSet, or marks it as the most recently used if
+// already present. If the LRUSet is full, the least recently used element is
+// evicted first.
+func (lru *LRUSet[T]) Add(s T) {
+	if lru.order.MoveToEnd(s) {
+		return
+	}
+	if lru.order.Len() == lru.capacity {
+		evictFirst(lru.order, lru.onEvict)
+	}
+	lru.order.Add(s)
+}
+
+// Remove removes an element from an LRUSet.
+func (lru *LRUSet[T]) Remove(s T) {
+	lru.order.Remove(s)
+}
+
+// AddAll adds a slice of elements to an LRUSet in order.
+func (lru *LRUSet[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		lru.Add(s)
+	}
+}
+
+// FIFOSet is a generic, not threadsafe set data structure holding at most a
+// fixed number of elements. When full, adding an element evicts the one
+// added first, regardless of use.
+type FIFOSet[T comparable] struct {
+	order    *OrderedSet[T]
+	capacity int
+	onEvict  func(T)
+}
+
+// NewFIFO creates a new FIFOSet holding at most capacity elements. A
+// capacity lower than one is treated as one.
+func NewFIFO[T comparable](capacity int) *FIFOSet[T] {
+	return &FIFOSet[T]{order: NewOrdered[T](), capacity: max(capacity, 1)}
+}
+
+// OnEvict registers a callback called with every element evicted to make
+// room for a new one. It is not called for elements removed with Remove.
+func (fifo *FIFOSet[T]) OnEvict(fn func(T)) {
+	fifo.onEvict = fn
+}
+
+// Len returns the number of elements in a FIFOSet.
+func (fifo *FIFOSet[T]) Len() int {
+	return fifo.order.Len()
+}
+
+// Cap returns the maximum number of elements in a FIFOSet.
+func (fifo *FIFOSet[T]) Cap() int {
+	return fifo.capacity
+}
+
+// ToSlice returns a slice of elements from a FIFOSet, oldest first.
+func (fifo *FIFOSet[T]) ToSlice() []T {
+	return fifo.order.ToSlice()
+}
+
+// All returns an iterator over the elements of a FIFOSet, oldest first.
+func (fifo *FIFOSet[T]) All() iter.Seq[T] {
+	return fifo.order.All()
+}
+
+// Contains returns true if a FIFOSet contains an element.
+func (fifo *FIFOSet[T]) Contains(s T) bool {
+	return fifo.order.Contains(s)
+}
+
+// Add adds an element to a FIFOSet. Adding an element already present does
+// not change its position. If the FIFOSet is full, the oldest element is
+// evicted first.
+func (fifo *FIFOSet[T]) Add(s T) {
+	if fifo.order.Contains(s) {
+		return
+	}
+	if fifo.order.Len() == fifo.capacity {
+		evictFirst(fifo.order, fifo.onEvict)
+	}
+	fifo.order.Add(s)
+}
+
+// Remove removes an element from a FIFOSet.
+func (fifo *FIFOSet[T]) Remove(s T) {
+	fifo.order.Remove(s)
+}
+
+// AddAll adds a slice of elements to a FIFOSet in order.
+func (fifo *FIFOSet[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		fifo.Add(s)
+	}
+}
+
+// LFUSet is a generic, not threadsafe set data structure holding at most a
+// fixed number of elements. When full, adding an element evicts the least
+// frequently used one, the least recently used among those with the same
+// frequency. Both Add and Contains count as a use.
+type LFUSet[T comparable] struct {
+	freq     map[T]int
+	buckets  map[int]*OrderedSet[T]
+	minFreq  int
+	capacity int
+	onEvict  func(T)
+}
+
+// NewLFU creates a new LFUSet holding at most capacity elements. A capacity
+// lower than one is treated as one.
+func NewLFU[T comparable](capacity int) *LFUSet[T] {
+	return &LFUSet[T]{
+		freq:     make(map[T]int),
+		buckets:  make(map[int]*OrderedSet[T]),
+		capacity: max(capacity, 1),
+	}
+}
+
+// OnEvict registers a callback called with every element evicted to make
+// room for a new one. It is not called for elements removed with Remove.
+func (lfu *LFUSet[T]) OnEvict(fn func(T)) {
+	lfu.onEvict = fn
+}
+
+// Len returns the number of elements in an LFUSet.
+func (lfu *LFUSet[T]) Len() int {
+	return len(lfu.freq)
+}
+
+// Cap returns the maximum number of elements in an LFUSet.
+func (lfu *LFUSet[T]) Cap() int {
+	return lfu.capacity
+}
+
+// Frequency returns how many times an element was used since it was added,
+// or zero if it is not present. It does not count as a use.
+func (lfu *LFUSet[T]) Frequency(s T) int {
+	return lfu.freq[s]
+}
+
+// ToSlice returns a slice of elements from an LFUSet in eviction order, from
+// the least to the most frequently used.
+func (lfu *LFUSet[T]) ToSlice() []T {
+	freqs := make([]int, 0, len(lfu.buckets))
+	for f := range lfu.buckets {
+		freqs = append(freqs, f)
+	}
+	slices.Sort(freqs)
+	slice := make([]T, 0, len(lfu.freq))
+	for _, f := range freqs {
+		slice = append(slice, lfu.buckets[f].ToSlice()...)
+	}
+	return slice
+}
+
+// All returns an iterator over the elements of an LFUSet in eviction order,
+// from the least to the most frequently used. Iterating does not count as a
+// use. It iterates a snapshot, so the LFUSet may be used while iterating.
+func (lfu *LFUSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, s := range lfu.ToSlice() {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if an LFUSet contains an element, counting it as a
+// use.
+func (lfu *LFUSet[T]) Contains(s T) bool {
+	if _, ok := lfu.freq[s]; !ok {
+		return false
+	}
+	lfu.touch(s)
+	return true
+}
+
+// Peek returns true if an LFUSet contains an element, without counting it as
+// a use.
+func (lfu *LFUSet[T]) Peek(s T) bool {
+	_, ok := lfu.freq[s]
+	return ok
+}
+
+// Add adds an element to an LFUSet, or counts it as a use if already
+// present. If the LFUSet is full, the least frequently used element is
+// evicted first.
+func (lfu *LFUSet[T]) Add(s T) {
+	if _, ok := lfu.freq[s]; ok {
+		lfu.touch(s)
+		return
+	}
+	if len(lfu.freq) == lfu.capacity {
+		if _, ok := lfu.buckets[lfu.minFreq]; !ok {
+			lfu.minFreq = lfu.lowestFreq()
+		}
+		bucket := lfu.buckets[lfu.minFreq]
+		victim, _ := bucket.First()
+		lfu.unlink(victim)
+		if lfu.onEvict != nil {
+			lfu.onEvict(victim)
+		}
+	}
+	lfu.freq[s] = 1
+	lfu.bucket(1).Add(s)
+	lfu.minFreq = 1
+}
+
+// Remove removes an element from an LFUSet.
+func (lfu *LFUSet[T]) Remove(s T) {
+	if _, ok := lfu.freq[s]; ok {
+		lfu.unlink(s)
+	}
+}
+
+// AddAll adds a slice of elements to an LFUSet in order.
+func (lfu *LFUSet[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		lfu.Add(s)
+	}
+}
+
+// touch moves a present element to the bucket of the next frequency.
+func (lfu *LFUSet[T]) touch(s T) {
+	f := lfu.freq[s]
+	lfu.unlink(s)
+	if f == lfu.minFreq {
+		if _, ok := lfu.buckets[f]; !ok {
+			lfu.minFreq = f + 1
+		}
+	}
+	lfu.freq[s] = f + 1
+	lfu.bucket(f + 1).Add(s)
+}
+
+// unlink removes a present element and drops its bucket if left empty.
+// minFreq may then point to a missing bucket.
+func (lfu *LFUSet[T]) unlink(s T) {
+	f := lfu.freq[s]
+	delete(lfu.freq, s)
+	bucket := lfu.buckets[f]
+	bucket.Remove(s)
+	if bucket.Len() == 0 {
+		delete(lfu.buckets, f)
+	}
+}
+
+func (lfu *LFUSet[T]) bucket(f int) *OrderedSet[T] {
+	bucket, ok := lfu.buckets[f]
+	if !ok {
+		bucket = NewOrdered[T]()
+		lfu.buckets[f] = bucket
+	}
+	return bucket
+}
+
+func (lfu *LFUSet[T]) lowestFreq() int {
+	lowest := 0
+	for f := range lfu.buckets {
+		if lowest == 0 || f < lowest {
+			lowest = f
+		}
+	}
+	return lowest
+}
+
+// evictFirst removes the first element of an OrderedSet, passing it to
+// onEvict if set.
+func evictFirst[T comparable](order *OrderedSet[T], onEvict func(T)) {
+	victim, _ := order.First()
+	order.Remove(victim)
+	if onEvict != nil {
+		onEvict(victim)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUSet(t *testing.T) {
+	lru := NewLRU[string](3)
+	evicted := make([]string, 0)
+	lru.OnEvict(func(s string) { evicted = append(evicted, s) })
+	require.Equal(t, 3, lru.Cap())
+
+	lru.AddAll([]string{"a", "b", "c"})
+	require.True(t, lru.Contains("a"))
+	lru.Add("b")
+	require.Equal(t, []string{"c", "a", "b"}, lru.ToSlice())
+
+	lru.Add("d")
+	require.Equal(t, []string{"c"}, evicted)
+	require.Equal(t, 3, lru.Len())
+	require.False(t, lru.Peek("c"))
+	require.False(t, lru.Contains("c"))
+
+	// Peek does not count as a use
+	require.True(t, lru.Peek("a"))
+	lru.Add("e")
+	require.Equal(t, []string{"c", "a"}, evicted)
+	require.Equal(t, []string{"b", "d", "e"}, lru.ToSlice())
+
+	lru.Remove("d")
+	lru.Remove("x")
+	lru.Add("f")
+	require.Equal(t, []string{"c", "a"}, evicted)
+	require.Equal(t, []string{"b", "e", "f"}, lru.ToSlice())
+	require.Equal(t, []string{"b", "e"}, firstN(lru.All(), 2))
+
+	require.Equal(t, 1, NewLRU[int](0).Cap())
+}
+
+func TestFIFOSet(t *testing.T) {
+	fifo := NewFIFO[int](3)
+	evicted := make([]int, 0)
+	fifo.OnEvict(func(s int) { evicted = append(evicted, s) })
+	require.Equal(t, 3, fifo.Cap())
+
+	fifo.AddAll([]int{1, 2, 3})
+	require.True(t, fifo.Contains(1))
+	fifo.Add(1)
+	fifo.Add(4)
+	require.Equal(t, []int{1}, evicted)
+	require.Equal(t, []int{2, 3, 4}, fifo.ToSlice())
+	require.Equal(t, 3, fifo.Len())
+
+	fifo.Remove(3)
+	fifo.AddAll([]int{5, 6})
+	require.Equal(t, []int{1, 2}, evicted)
+	require.Equal(t, []int{4, 5, 6}, fifo.ToSlice())
+	require.False(t, fifo.Contains(2))
+	require.Equal(t, []int{4, 5, 6}, firstN(fifo.All(), 5))
+
+	require.Equal(t, 1, NewFIFO[int](-3).Cap())
+}
+
+func TestLFUSet(t *testing.T) {
+	lfu := NewLFU[string](3)
+	evicted := make([]string, 0)
+	lfu.OnEvict(func(s string) { evicted = append(evicted, s) })
+	require.Equal(t, 3, lfu.Cap())
+
+	lfu.AddAll([]string{"a", "b", "c"})
+	lfu.Contains("a")
+	lfu.Contains("a")
+	lfu.Add("b")
+	require.Equal(t, 3, lfu.Frequency("a"))
+	require.Equal(t, 2, lfu.Frequency("b"))
+	require.Equal(t, 1, lfu.Frequency("c"))
+	require.Equal(t, 0, lfu.Frequency("x"))
+	require.Equal(t, []string{"c", "b", "a"}, lfu.ToSlice())
+
+	lfu.Add("d")
+	require.Equal(t, []string{"c"}, evicted)
+	require.False(t, lfu.Peek("c"))
+
+	// d is least frequent, ties broken by recency
+	lfu.Add("e")
+	require.Equal(t, []string{"c", "d"}, evicted)
+	lfu.Contains("e")
+	require.True(t, lfu.Peek("b"))
+	require.Equal(t, 2, lfu.Frequency("b"))
+	lfu.Add("f")
+	require.Equal(t, []string{"c", "d", "b"}, evicted)
+	require.Equal(t, []string{"f", "e", "a"}, lfu.ToSlice())
+
+	// removing leaves the lowest frequency pointing to a missing bucket
+	lfu.Remove("f")
+	lfu.Remove("x")
+	lfu.Contains("e")
+	lfu.Add("g")
+	lfu.Contains("g")
+	lfu.Add("h")
+	require.Equal(t, []string{"c", "d", "b", "g"}, evicted)
+	require.Equal(t, []string{"h", "a", "e"}, lfu.ToSlice())
+	require.Equal(t, []string{"h"}, firstN(lfu.All(), 1))
+
+	require.Equal(t, 1, NewLFU[int](0).Cap())
+}
+
+func TestLFUSetMatchesNaive(t *testing.T) {
+	const capacity = 8
+	rnd := rand.New(rand.NewSource(1))
+	lfu := NewLFU[int](capacity)
+	var evicted []int
+	lfu.OnEvict(func(s int) { evicted = append(evicted, s) })
+
+	// naive keeps frequency and last use time of present elements
+	freq := make(map[int]int)
+	used := make(map[int]int)
+	for step := 0; step < 5000; step++ {
+		s := rnd.Intn(20)
+		evicted = nil
+		switch rnd.Intn(4) {
+		case 0:
+			lfu.Remove(s)
+			delete(freq, s)
+			delete(used, s)
+		case 1:
+			_, ok := freq[s]
+			require.Equal(t, ok, lfu.Contains(s))
+			if ok {
+				freq[s]++
+				used[s] = step
+			}
+		default:
+			var expected []int
+			if _, ok := freq[s]; !ok && len(freq) == capacity {
+				victim := -1
+				for e := range freq {
+					if victim < 0 || freq[e] < freq[victim] || freq[e] == freq[victim] && used[e] < used[victim] {
+						victim = e
+					}
+				}
+				delete(freq, victim)
+				delete(used, victim)
+				expected = []int{victim}
+			}
+			lfu.Add(s)
+			freq[s]++
+			used[s] = step
+			require.Equal(t, expected, evicted)
+		}
+		require.Equal(t, len(freq), lfu.Len())
+	}
+	for s, f := range freq {
+		require.Equal(t, f, lfu.Frequency(s))
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"iter"
+	"sync"
+)
+
+// ConcurrentSet is a generic, threadsafe set data structure. It wraps a Set
+// and guards it with a sync.RWMutex, so it can be shared between goroutines.
+// A ConcurrentSet must not be copied after first use.
+type ConcurrentSet[T comparable] struct {
+	mu  sync.RWMutex
+	set Set[T]
+}
+
+// NewConcurrent creates a new ConcurrentSet.
+func NewConcurrent[T comparable]() *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{set: New[T]()}
+}
+
+// NewConcurrentFromSlice creates a new ConcurrentSet from a slice of comparable.
+func NewConcurrentFromSlice[T comparable](slice []T) *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{set: NewFromSlice(slice)}
+}
+
+// NewConcurrentFromSet creates a new ConcurrentSet holding a copy of a Set.
+func NewConcurrentFromSet[T comparable](set Set[T]) *ConcurrentSet[T] {
+	return &ConcurrentSet[T]{set: set.clone()}
+}
+
+// Len returns the number of elements in a ConcurrentSet.
+func (cs *ConcurrentSet[T]) Len() int {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return len(cs.set)
+}
+
+// Snapshot returns a copy of the ConcurrentSet content as a plain Set.
+func (cs *ConcurrentSet[T]) Snapshot() Set[T] {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.set.clone()
+}
+
+// ToSlice returns an unordered slice of elements from a ConcurrentSet.
+func (cs *ConcurrentSet[T]) ToSlice() []T {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.set.ToSlice()
+}
+
+// All returns an iterator over a snapshot of the elements of a ConcurrentSet,
+// in no particular order. Changes made while iterating are not observed.
+func (cs *ConcurrentSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s := range cs.Snapshot() {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if a ConcurrentSet contains an element.
+func (cs *ConcurrentSet[T]) Contains(s T) bool {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.set.Contains(s)
+}
+
+// Equals returns true if two ConcurrentSets are equal.
+func (cs *ConcurrentSet[T]) Equals(other *ConcurrentSet[T]) bool {
+	// other is copied first, so the two locks are never held together
+	o := other.Snapshot()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.set.Equals(o)
+}
+
+// IsSubsetOf returns true if a ConcurrentSet is a subset of another
+// ConcurrentSet (they can be equal).
+func (cs *ConcurrentSet[T]) IsSubsetOf(other *ConcurrentSet[T]) bool {
+	o := other.Snapshot()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.set.IsSubsetOf(o)
+}
+
+// IsProperSubsetOf returns true if a ConcurrentSet is a proper subset of
+// another ConcurrentSet (they cannot be equal).
+func (cs *ConcurrentSet[T]) IsProperSubsetOf(other *ConcurrentSet[T]) bool {
+	o := other.Snapshot()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return cs.set.IsProperSubsetOf(o)
+}
+
+// Add adds an element to a ConcurrentSet.
+func (cs *ConcurrentSet[T]) Add(s T) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.set.Add(s)
+}
+
+// Remove removes an element from a ConcurrentSet.
+func (cs *ConcurrentSet[T]) Remove(s T) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.set.Remove(s)
+}
+
+// AddAll adds a slice of elements to a ConcurrentSet.
+func (cs *ConcurrentSet[T]) AddAll(slice []T) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.set.AddAll(slice)
+}
+
+// RemoveAll removes a slice of elements from a ConcurrentSet.
+func (cs *ConcurrentSet[T]) RemoveAll(slice []T) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	cs.set.RemoveAll(slice)
+}
+
+// AddIfAbsent adds an element to a ConcurrentSet and returns true if it was
+// not already present. The check and the insertion happen atomically.
+func (cs *ConcurrentSet[T]) AddIfAbsent(s T) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.set.Contains(s) {
+		return false
+	}
+	cs.set.Add(s)
+	return true
+}
+
+// RemoveIfPresent removes an element from a ConcurrentSet and returns true if
+// it was present. The check and the removal happen atomically.
+func (cs *ConcurrentSet[T]) RemoveIfPresent(s T) bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if !cs.set.Contains(s) {
+		return false
+	}
+	cs.set.Remove(s)
+	return true
+}
+
+// Union returns the union of two ConcurrentSets as new ConcurrentSet.
+func (cs *ConcurrentSet[T]) Union(other *ConcurrentSet[T]) *ConcurrentSet[T] {
+	o := other.Snapshot()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return &ConcurrentSet[T]{set: cs.set.Union(o)}
+}
+
+// Intersection returns the intersection of two ConcurrentSets as new
+// ConcurrentSet.
+func (cs *ConcurrentSet[T]) Intersection(other *ConcurrentSet[T]) *ConcurrentSet[T] {
+	o := other.Snapshot()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return &ConcurrentSet[T]{set: cs.set.Intersection(o)}
+}
+
+// Difference returns the difference of two ConcurrentSets as new
+// ConcurrentSet.
+func (cs *ConcurrentSet[T]) Difference(other *ConcurrentSet[T]) *ConcurrentSet[T] {
+	o := other.Snapshot()
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+	return &ConcurrentSet[T]{set: cs.set.Difference(o)}
+}
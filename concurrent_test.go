@@ -0,0 +1,265 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentSetBasic(t *testing.T) {
+	cs := NewConcurrentFromSlice([]string{"a", "b", "c"})
+	require.Equal(t, 3, cs.Len())
+	require.True(t, cs.Contains("a"))
+	require.False(t, cs.Contains("d"))
+
+	cs.Add("d")
+	cs.Remove("a")
+	cs.AddAll([]string{"e", "f"})
+	cs.RemoveAll([]string{"b", "x"})
+	require.Equal(t, Set[string]{"c": struct{}{}, "d": struct{}{}, "e": struct{}{}, "f": struct{}{}}, cs.Snapshot())
+
+	actual := cs.ToSlice()
+	slices.Sort(actual)
+	require.Equal(t, []string{"c", "d", "e", "f"}, actual)
+}
+
+func TestConcurrentSetFromSetCopies(t *testing.T) {
+	s := NewFromSlice([]int{1, 2})
+	cs := NewConcurrentFromSet(s)
+	s.Add(3)
+	require.False(t, cs.Contains(3))
+
+	snap := cs.Snapshot()
+	snap.Add(4)
+	require.False(t, cs.Contains(4))
+}
+
+func TestConcurrentSetAddIfAbsent(t *testing.T) {
+	cases := []struct {
+		name     string
+		set      []string
+		s        string
+		expected bool
+	}{
+		{
+			name:     "empty set",
+			set:      []string{},
+			s:        "a",
+			expected: true,
+		},
+		{
+			name:     "absent",
+			set:      []string{"a", "b"},
+			s:        "c",
+			expected: true,
+		},
+		{
+			name:     "present",
+			set:      []string{"a", "b"},
+			s:        "a",
+			expected: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cs := NewConcurrentFromSlice(c.set)
+			actual := cs.AddIfAbsent(c.s)
+			require.Equal(t, c.expected, actual, "expected %v, got %v", c.expected, actual)
+			require.True(t, cs.Contains(c.s))
+		})
+	}
+}
+
+func TestConcurrentSetRemoveIfPresent(t *testing.T) {
+	cases := []struct {
+		name     string
+		set      []string
+		s        string
+		expected bool
+	}{
+		{
+			name:     "empty set",
+			set:      []string{},
+			s:        "a",
+			expected: false,
+		},
+		{
+			name:     "absent",
+			set:      []string{"a", "b"},
+			s:        "c",
+			expected: false,
+		},
+		{
+			name:     "present",
+			set:      []string{"a", "b"},
+			s:        "a",
+			expected: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cs := NewConcurrentFromSlice(c.set)
+			actual := cs.RemoveIfPresent(c.s)
+			require.Equal(t, c.expected, actual, "expected %v, got %v", c.expected, actual)
+			require.False(t, cs.Contains(c.s))
+		})
+	}
+}
+
+func TestConcurrentSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          []string
+		other        []string
+		union        Set[string]
+		intersection Set[string]
+		difference   Set[string]
+		equals       bool
+		subset       bool
+		properSubset bool
+	}{
+		{
+			name:         "empty sets",
+			set:          []string{},
+			other:        []string{},
+			union:        Set[string]{},
+			intersection: Set[string]{},
+			difference:   Set[string]{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "equal sets",
+			set:          []string{"a", "b"},
+			other:        []string{"a", "b"},
+			union:        Set[string]{"a": struct{}{}, "b": struct{}{}},
+			intersection: Set[string]{"a": struct{}{}, "b": struct{}{}},
+			difference:   Set[string]{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "proper subset",
+			set:          []string{"a"},
+			other:        []string{"a", "b"},
+			union:        Set[string]{"a": struct{}{}, "b": struct{}{}},
+			intersection: Set[string]{"a": struct{}{}},
+			difference:   Set[string]{},
+			equals:       false,
+			subset:       true,
+			properSubset: true,
+		},
+		{
+			name:         "partial overlap",
+			set:          []string{"a", "b", "c"},
+			other:        []string{"b", "c", "d"},
+			union:        Set[string]{"a": struct{}{}, "b": struct{}{}, "c": struct{}{}, "d": struct{}{}},
+			intersection: Set[string]{"b": struct{}{}, "c": struct{}{}},
+			difference:   Set[string]{"a": struct{}{}},
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cs := NewConcurrentFromSlice(c.set)
+			other := NewConcurrentFromSlice(c.other)
+			require.Equal(t, c.union, cs.Union(other).Snapshot())
+			require.Equal(t, c.intersection, cs.Intersection(other).Snapshot())
+			require.Equal(t, c.difference, cs.Difference(other).Snapshot())
+			require.Equal(t, c.equals, cs.Equals(other))
+			require.Equal(t, c.subset, cs.IsSubsetOf(other))
+			require.Equal(t, c.properSubset, cs.IsProperSubsetOf(other))
+		})
+	}
+}
+
+func TestConcurrentSetSelfOperations(t *testing.T) {
+	cs := NewConcurrentFromSlice([]int{1, 2, 3})
+	require.True(t, cs.Equals(cs))
+	require.True(t, cs.IsSubsetOf(cs))
+	require.Equal(t, 3, cs.Union(cs).Len())
+	require.Equal(t, 0, cs.Difference(cs).Len())
+}
+
+func TestConcurrentSetStress(t *testing.T) {
+	const (
+		workers = 16
+		perW    = 1000
+	)
+	cs := NewConcurrent[int]()
+	other := NewConcurrentFromSlice(randomInts(100))
+	var added, removed atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perW; i++ {
+				v := i % 257
+				if cs.AddIfAbsent(v) {
+					added.Add(1)
+				}
+				cs.Contains(v)
+				cs.Add(v + 1000)
+				cs.AddAll([]int{v + 2000, v + 3000})
+				if i%3 == w%3 && cs.RemoveIfPresent(v) {
+					removed.Add(1)
+				}
+				cs.RemoveAll([]int{v + 3000})
+				if i%100 == 0 {
+					cs.Len()
+					cs.ToSlice()
+					cs.Union(other)
+					cs.Intersection(other)
+					cs.Difference(other)
+					cs.Equals(other)
+					cs.IsSubsetOf(other)
+					other.IsSubsetOf(cs)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	// every successful AddIfAbsent is either still present or matched by
+	// exactly one successful RemoveIfPresent
+	present := 0
+	for v := 0; v < 257; v++ {
+		if cs.Contains(v) {
+			present++
+		}
+	}
+	require.Equal(t, added.Load()-removed.Load(), int64(present))
+}
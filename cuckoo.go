@@ -0,0 +1,341 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Defaults used by NewCuckoo, and by NewCuckooWithParams for parameters out
+// of range.
+const (
+	DefaultCuckooFingerprintBits = 16
+	DefaultCuckooBucketSize      = 4
+)
+
+// cuckooVersion is the first byte of every binary encoded CuckooFilter.
+const cuckooVersion = 1
+
+// cuckooMaxKicks bounds the number of fingerprints moved by one Add before
+// the filter is considered full.
+const cuckooMaxKicks = 500
+
+// ErrFilterFull is returned when an element cannot be added to a filter.
+var ErrFilterFull = errors.New("set: filter is full")
+
+// CuckooFilter is a generic, not threadsafe probabilistic set supporting
+// removal. It stores a short fingerprint of every element in one of two
+// candidate buckets; like a BloomFilter it never reports an added element as
+// absent, but may report a missing one as present.
+//
+// Fingerprints are counted rather than deduplicated: adding an element twice
+// stores it twice and it must be removed twice. Only remove elements that
+// were added, otherwise the fingerprint of another element may be removed.
+type CuckooFilter[T comparable] struct {
+	slots           []uint32 // buckets of bucketSize fingerprints, 0 is empty
+	bucketSize      int
+	bucketMask      uint64
+	fingerprintBits int
+	count           int
+	rnd             uint64 // state of the generator choosing victims
+}
+
+// NewCuckoo creates a new CuckooFilter for capacity elements with the default
+// fingerprint and bucket sizes, which give a false positive rate of about
+// 0.01%.
+func NewCuckoo[T comparable](capacity int) *CuckooFilter[T] {
+	return NewCuckooWithParams[T](capacity, DefaultCuckooFingerprintBits, DefaultCuckooBucketSize)
+}
+
+// NewCuckooWithParams creates a new CuckooFilter for capacity elements with
+// fingerprints of fingerprintBits, between 1 and 32, and buckets of
+// bucketSize fingerprints. Longer fingerprints lower the false positive
+// rate, larger buckets raise the reachable load factor and the false
+// positive rate.
+func NewCuckooWithParams[T comparable](capacity, fingerprintBits, bucketSize int) *CuckooFilter[T] {
+	if fingerprintBits < 1 || fingerprintBits > 32 {
+		fingerprintBits = DefaultCuckooFingerprintBits
+	}
+	if bucketSize < 1 {
+		bucketSize = DefaultCuckooBucketSize
+	}
+	// load factors safely reachable before inserts start failing
+	load := 0.9
+	switch bucketSize {
+	case 1:
+		load = 0.4
+	case 2:
+		load = 0.8
+	}
+	buckets := math.Ceil(float64(max(capacity, 1)) / float64(bucketSize) / load)
+	return newCuckoo[T](nextPowerOfTwo(uint64(buckets)), fingerprintBits, bucketSize)
+}
+
+// cuckooFromSetDoublings is how many times NewCuckooFromSet doubles the
+// capacity of a filter that the elements of a Set do not fit in.
+const cuckooFromSetDoublings = 4
+
+// NewCuckooFromSet creates a new CuckooFilter with the default parameters
+// holding the elements of a Set. It starts sized for them and doubles the
+// number of buckets up to 4 times until they all fit, then returns
+// ErrFilterFull. This happens when more than 2 × DefaultCuckooBucketSize
+// elements share a hash, such as several NaN keys.
+func NewCuckooFromSet[T comparable](set Set[T]) (*CuckooFilter[T], error) {
+	capacity := max(len(set), 1)
+	var err error
+	for range cuckooFromSetDoublings + 1 {
+		cf := NewCuckoo[T](capacity)
+		if err = cf.addSet(set); err == nil {
+			return cf, nil
+		}
+		capacity *= 2
+	}
+	return nil, err
+}
+
+// addSet adds the elements of a Set, stopping at the first that does not
+// fit.
+func (cf *CuckooFilter[T]) addSet(set Set[T]) error {
+	for s := range set {
+		if err := cf.Add(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newCuckoo[T comparable](buckets uint64, fingerprintBits, bucketSize int) *CuckooFilter[T] {
+	return &CuckooFilter[T]{
+		slots:           make([]uint32, buckets*uint64(bucketSize)),
+		bucketSize:      bucketSize,
+		bucketMask:      buckets - 1,
+		fingerprintBits: fingerprintBits,
+		rnd:             1,
+	}
+}
+
+func nextPowerOfTwo(n uint64) uint64 {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len64(n-1)
+}
+
+// Len returns the number of elements in a CuckooFilter.
+func (cf *CuckooFilter[T]) Len() int {
+	return cf.count
+}
+
+// Cap returns the number of fingerprints a CuckooFilter has room for.
+// Inserts usually start failing before it is reached.
+func (cf *CuckooFilter[T]) Cap() int {
+	return len(cf.slots)
+}
+
+// LoadFactor returns the fraction of fingerprint slots in use.
+func (cf *CuckooFilter[T]) LoadFactor() float64 {
+	return float64(cf.count) / float64(len(cf.slots))
+}
+
+// Add adds an element to a CuckooFilter. It returns ErrFilterFull, leaving
+// the filter unchanged, if no room could be made for it.
+func (cf *CuckooFilter[T]) Add(s T) error {
+	fp, i1, i2 := cf.locate(s)
+	if cf.insert(i1, fp) || cf.insert(i2, fp) {
+		cf.count++
+		return nil
+	}
+
+	// evict fingerprints to their alternate bucket, remembering the moves
+	// to undo them if no empty slot is found
+	type move struct {
+		slot int
+		fp   uint32
+	}
+	moves := make([]move, 0, cuckooMaxKicks)
+	i := i1
+	if cf.random()&1 == 1 {
+		i = i2
+	}
+	for kick := 0; kick < cuckooMaxKicks; kick++ {
+		slot := int(i)*cf.bucketSize + int(cf.random()%uint64(cf.bucketSize))
+		moves = append(moves, move{slot: slot, fp: cf.slots[slot]})
+		fp, cf.slots[slot] = cf.slots[slot], fp
+		i = cf.alternate(i, fp)
+		if cf.insert(i, fp) {
+			cf.count++
+			return nil
+		}
+	}
+	for k := len(moves) - 1; k >= 0; k-- {
+		cf.slots[moves[k].slot] = moves[k].fp
+	}
+	return fmt.Errorf("%w: %d of %d slots used", ErrFilterFull, cf.count, len(cf.slots))
+}
+
+// Remove removes one occurrence of an element from a CuckooFilter and
+// reports whether its fingerprint was found.
+func (cf *CuckooFilter[T]) Remove(s T) bool {
+	fp, i1, i2 := cf.locate(s)
+	for _, i := range []uint64{i1, i2} {
+		bucket := cf.bucket(i)
+		for j, f := range bucket {
+			if f == fp {
+				bucket[j] = 0
+				cf.count--
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MayContain returns false if an element is certainly not in a CuckooFilter,
+// and true if it probably is.
+func (cf *CuckooFilter[T]) MayContain(s T) bool {
+	fp, i1, i2 := cf.locate(s)
+	for _, i := range []uint64{i1, i2} {
+		for _, f := range cf.bucket(i) {
+			if f == fp {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// locate returns the fingerprint of an element and its two buckets.
+func (cf *CuckooFilter[T]) locate(s T) (uint32, uint64, uint64) {
+	h1, h2 := stableHash(s)
+	fp := uint32(h2 & (1<<cf.fingerprintBits - 1))
+	if fp == 0 {
+		fp = 1
+	}
+	i1 := h1 & cf.bucketMask
+	return fp, i1, cf.alternate(i1, fp)
+}
+
+// alternate returns the other bucket of a fingerprint stored in bucket i. It
+// is its own inverse, so it can be computed from either bucket.
+func (cf *CuckooFilter[T]) alternate(i uint64, fp uint32) uint64 {
+	return (i ^ mix64(uint64(fp))) & cf.bucketMask
+}
+
+func (cf *CuckooFilter[T]) bucket(i uint64) []uint32 {
+	start := int(i) * cf.bucketSize
+	return cf.slots[start : start+cf.bucketSize]
+}
+
+// insert stores a fingerprint in an empty slot of bucket i, if any.
+func (cf *CuckooFilter[T]) insert(i uint64, fp uint32) bool {
+	bucket := cf.bucket(i)
+	for j, f := range bucket {
+		if f == 0 {
+			bucket[j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// random returns the next value of a xorshift generator, so victims are
+// chosen reproducibly.
+func (cf *CuckooFilter[T]) random() uint64 {
+	cf.rnd ^= cf.rnd << 13
+	cf.rnd ^= cf.rnd >> 7
+	cf.rnd ^= cf.rnd << 17
+	return cf.rnd
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The encoding holds a
+// version byte, the fingerprint bits, bucket size and number of buckets as
+// uvarints and every slot as a little endian fingerprint of the fewest whole
+// bytes.
+func (cf *CuckooFilter[T]) MarshalBinary() ([]byte, error) {
+	width := (cf.fingerprintBits + 7) / 8
+	buf := make([]byte, 0, 1+3*binary.MaxVarintLen64+width*len(cf.slots))
+	buf = append(buf, cuckooVersion)
+	buf = binary.AppendUvarint(buf, uint64(cf.fingerprintBits))
+	buf = binary.AppendUvarint(buf, uint64(cf.bucketSize))
+	buf = binary.AppendUvarint(buf, cf.bucketMask+1)
+	var b [4]byte
+	for _, f := range cf.slots {
+		binary.LittleEndian.PutUint32(b[:], f)
+		buf = append(buf, b[:width]...)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, replacing the
+// content of a CuckooFilter.
+func (cf *CuckooFilter[T]) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: data too short", ErrInvalidEncoding)
+	}
+	if data[0] != cuckooVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, data[0])
+	}
+	data = data[1:]
+	var params [3]uint64
+	for i := range params {
+		x, err := readUvarint(&data)
+		if err != nil {
+			return err
+		}
+		params[i] = x
+	}
+	fingerprintBits, bucketSize, buckets := params[0], params[1], params[2]
+	if fingerprintBits < 1 || fingerprintBits > 32 || bucketSize < 1 || buckets < 1 || buckets&(buckets-1) != 0 {
+		return fmt.Errorf("%w: cuckoo filter of %d bit fingerprints, buckets of %d and %d buckets",
+			ErrInvalidEncoding, fingerprintBits, bucketSize, buckets)
+	}
+	width := (fingerprintBits + 7) / 8
+	overflow, slots := bits.Mul64(bucketSize, buckets)
+	if hi, size := bits.Mul64(slots, width); overflow != 0 || hi != 0 || size != uint64(len(data)) {
+		return fmt.Errorf("%w: %d buckets of %d do not fit in %d bytes", ErrInvalidEncoding, buckets, bucketSize, len(data))
+	}
+
+	result := newCuckoo[T](buckets, int(fingerprintBits), int(bucketSize))
+	for i := range result.slots {
+		var b [4]byte
+		copy(b[:], data[:width])
+		data = data[width:]
+		f := binary.LittleEndian.Uint32(b[:])
+		if uint64(f)>>fingerprintBits != 0 {
+			return fmt.Errorf("%w: fingerprint %d wider than %d bits", ErrInvalidEncoding, f, fingerprintBits)
+		}
+		result.slots[i] = f
+		if f != 0 {
+			result.count++
+		}
+	}
+	*cf = *result
+	return nil
+}
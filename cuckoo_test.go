@@ -0,0 +1,247 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCuckooFilterBasic(t *testing.T) {
+	cf := NewCuckoo[string](100)
+	require.Equal(t, 0, cf.Len())
+	require.Equal(t, 0.0, cf.LoadFactor())
+	require.False(t, cf.MayContain("a"))
+
+	require.NoError(t, cf.Add("a"))
+	require.NoError(t, cf.Add("b"))
+	require.NoError(t, cf.Add("a"))
+	require.Equal(t, 3, cf.Len())
+	require.Equal(t, 3/float64(cf.Cap()), cf.LoadFactor())
+	require.True(t, cf.MayContain("a"))
+	require.True(t, cf.MayContain("b"))
+
+	// duplicates are counted
+	require.True(t, cf.Remove("a"))
+	require.True(t, cf.MayContain("a"))
+	require.True(t, cf.Remove("a"))
+	require.False(t, cf.MayContain("a"))
+	require.False(t, cf.Remove("a"))
+	require.Equal(t, 1, cf.Len())
+}
+
+func TestCuckooFilterParams(t *testing.T) {
+	cases := []struct {
+		name            string
+		capacity        int
+		fingerprintBits int
+		bucketSize      int
+		cap             int
+	}{
+		{name: "defaults", capacity: 1000, fingerprintBits: 16, bucketSize: 4, cap: 2048},
+		{name: "out of range", capacity: 1000, fingerprintBits: 33, bucketSize: 0, cap: 2048},
+		{name: "single slot buckets", capacity: 1000, fingerprintBits: 16, bucketSize: 1, cap: 4096},
+		{name: "large buckets", capacity: 1000, fingerprintBits: 8, bucketSize: 8, cap: 2048},
+		{name: "empty", capacity: 0, fingerprintBits: 4, bucketSize: 2, cap: 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cf := NewCuckooWithParams[int](c.capacity, c.fingerprintBits, c.bucketSize)
+			require.Equal(t, c.cap, cf.Cap())
+			for i := 0; i < c.capacity; i++ {
+				require.NoError(t, cf.Add(i))
+			}
+			for i := 0; i < c.capacity; i++ {
+				require.True(t, cf.MayContain(i))
+			}
+		})
+	}
+}
+
+func TestCuckooFilterFull(t *testing.T) {
+	cf := NewCuckooWithParams[int](64, 8, 4)
+	var err error
+	added := 0
+	for ; err == nil; added++ {
+		err = cf.Add(added)
+	}
+	added--
+	require.ErrorIs(t, err, ErrFilterFull)
+	require.Equal(t, added, cf.Len())
+	require.Greater(t, cf.LoadFactor(), 0.8)
+
+	// a failed Add leaves every element in place
+	for i := 0; i < added; i++ {
+		require.True(t, cf.MayContain(i), "element %d", i)
+	}
+	require.True(t, cf.Remove(0))
+	require.NoError(t, cf.Add(0))
+}
+
+func TestCuckooFilterFalsePositiveRate(t *testing.T) {
+	cases := []struct {
+		fingerprintBits int
+		maxRate         float64
+	}{
+		// the rate is about 2 * bucketSize / 2^fingerprintBits
+		{fingerprintBits: 8, maxRate: 0.04},
+		{fingerprintBits: 12, maxRate: 0.003},
+		{fingerprintBits: 16, maxRate: 0.0003},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprint(c.fingerprintBits), func(t *testing.T) {
+			const n, probes = 10000, 100000
+			cf := NewCuckooWithParams[string](n, c.fingerprintBits, 4)
+			for i := 0; i < n; i++ {
+				require.NoError(t, cf.Add("member-"+strconv.Itoa(i)))
+			}
+			positives := 0
+			for i := 0; i < probes; i++ {
+				if cf.MayContain("other-" + strconv.Itoa(i)) {
+					positives++
+				}
+			}
+			require.LessOrEqual(t, float64(positives)/probes, c.maxRate)
+		})
+	}
+}
+
+func TestCuckooFilterFromSet(t *testing.T) {
+	set := NewFromSlice(randomInts(5000))
+	cf, err := NewCuckooFromSet(set)
+	require.NoError(t, err)
+	require.Equal(t, len(set), cf.Len())
+	for s := range set {
+		require.True(t, cf.MayContain(s))
+	}
+	for s := range set {
+		require.True(t, cf.Remove(s))
+	}
+	require.Equal(t, 0, cf.Len())
+}
+
+func TestCuckooFilterFromSmallSets(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 3000; trial++ {
+		set := New[int]()
+		for n := rnd.Intn(100); n >= 0; n-- {
+			set.Add(rnd.Int())
+		}
+		cf, err := NewCuckooFromSet(set)
+		require.NoError(t, err)
+		require.Equal(t, len(set), cf.Len())
+		for s := range set {
+			require.True(t, cf.MayContain(s))
+		}
+	}
+	cf, err := NewCuckooFromSet(New[int]())
+	require.NoError(t, err)
+	require.Equal(t, 0, cf.Len())
+}
+
+func TestCuckooFilterFromSetFull(t *testing.T) {
+	// NaN keys are all distinct but share a hash, so no number of buckets
+	// holds more than two buckets of them
+	set := New[float64]()
+	for range 2*DefaultCuckooBucketSize + 1 {
+		set.Add(math.NaN())
+	}
+	cf, err := NewCuckooFromSet(set)
+	require.ErrorIs(t, err, ErrFilterFull)
+	require.Nil(t, cf)
+}
+
+func TestCuckooFilterBinary(t *testing.T) {
+	for _, bits := range []int{8, 12, 16, 32} {
+		t.Run(fmt.Sprint(bits), func(t *testing.T) {
+			cf := NewCuckooWithParams[string](100, bits, 2)
+			for i := 0; i < 100; i++ {
+				require.NoError(t, cf.Add(strconv.Itoa(i)))
+			}
+			data, err := cf.MarshalBinary()
+			require.NoError(t, err)
+
+			var decoded CuckooFilter[string]
+			require.NoError(t, decoded.UnmarshalBinary(data))
+			require.Equal(t, cf.slots, decoded.slots)
+			require.Equal(t, cf.Len(), decoded.Len())
+			for i := 0; i < 100; i++ {
+				require.True(t, decoded.MayContain(strconv.Itoa(i)))
+			}
+			require.True(t, decoded.Remove("0"))
+		})
+	}
+
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "bad version", data: []byte{2, 8, 1, 1, 0}},
+		{name: "truncated header", data: []byte{1, 8, 1}},
+		{name: "no fingerprint bits", data: []byte{1, 0, 1, 1, 0}},
+		{name: "wide fingerprints", data: []byte{1, 33, 1, 1, 0, 0, 0, 0, 0}},
+		{name: "no bucket size", data: []byte{1, 8, 0, 1}},
+		{name: "buckets not a power of two", data: []byte{1, 8, 1, 3, 0, 0, 0}},
+		{name: "truncated slots", data: []byte{1, 8, 2, 2, 0, 0, 0}},
+		{name: "extra slots", data: []byte{1, 8, 1, 1, 0, 0}},
+		{name: "fingerprint too wide", data: []byte{1, 4, 1, 1, 0x10}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var cf CuckooFilter[string]
+			require.ErrorIs(t, cf.UnmarshalBinary(c.data), ErrInvalidEncoding)
+		})
+	}
+}
+
+func FuzzCuckooFilterUnmarshalBinary(f *testing.F) {
+	cf := NewCuckooWithParams[int](64, 12, 2)
+	for s := 0; s < 16; s++ {
+		require.NoError(f, cf.Add(s))
+	}
+	data, err := cf.MarshalBinary()
+	require.NoError(f, err)
+	f.Add(data)
+	f.Add([]byte{1, 4, 1, 1, 0x05})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var cf CuckooFilter[int]
+		if err := cf.UnmarshalBinary(data); err != nil {
+			return
+		}
+		encoded, err := cf.MarshalBinary()
+		require.NoError(t, err)
+		var decoded CuckooFilter[int]
+		require.NoError(t, decoded.UnmarshalBinary(encoded))
+		require.Equal(t, cf, decoded)
+		cf.MayContain(1)
+	})
+}
@@ -0,0 +1,153 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+// DisjointSets is a generic, not threadsafe union-find structure partitioning
+// elements into disjoint sets. Find uses path compression and Union merges by
+// rank, so operations run in nearly constant amortized time.
+type DisjointSets[T comparable] struct {
+	parent     map[T]T
+	rank       map[T]int
+	components int
+}
+
+// NewDisjointSets creates a new, empty DisjointSets.
+func NewDisjointSets[T comparable]() *DisjointSets[T] {
+	return &DisjointSets[T]{parent: make(map[T]T), rank: make(map[T]int)}
+}
+
+// NewDisjointSetsFromSlice creates a new DisjointSets with every element of a
+// slice in its own set.
+func NewDisjointSetsFromSlice[T comparable](slice []T) *DisjointSets[T] {
+	ds := NewDisjointSets[T]()
+	for _, s := range slice {
+		ds.MakeSet(s)
+	}
+	return ds
+}
+
+// Len returns the number of elements in a DisjointSets.
+func (ds *DisjointSets[T]) Len() int {
+	return len(ds.parent)
+}
+
+// Components returns the number of disjoint sets.
+func (ds *DisjointSets[T]) Components() int {
+	return ds.components
+}
+
+// Contains returns true if an element has been added to a DisjointSets.
+func (ds *DisjointSets[T]) Contains(s T) bool {
+	_, ok := ds.parent[s]
+	return ok
+}
+
+// MakeSet adds an element in a new set of its own. It returns false and
+// does nothing if the element is already present.
+func (ds *DisjointSets[T]) MakeSet(s T) bool {
+	if _, ok := ds.parent[s]; ok {
+		return false
+	}
+	ds.parent[s] = s
+	ds.components++
+	return true
+}
+
+// Find returns the representative of the set holding an element, or false if
+// the element is not present. Two elements are in the same set if and only
+// if they have the same representative.
+func (ds *DisjointSets[T]) Find(s T) (T, bool) {
+	if _, ok := ds.parent[s]; !ok {
+		var zero T
+		return zero, false
+	}
+	return ds.find(s), true
+}
+
+// Union merges the sets holding two elements, adding any element not yet
+// present. It returns false if they were already in the same set.
+func (ds *DisjointSets[T]) Union(a, b T) bool {
+	ds.MakeSet(a)
+	ds.MakeSet(b)
+	ra, rb := ds.find(a), ds.find(b)
+	if ra == rb {
+		return false
+	}
+	switch {
+	case ds.rank[ra] < ds.rank[rb]:
+		ds.parent[ra] = rb
+	case ds.rank[ra] > ds.rank[rb]:
+		ds.parent[rb] = ra
+	default:
+		ds.parent[rb] = ra
+		ds.rank[ra]++
+	}
+	ds.components--
+	return true
+}
+
+// Connected returns true if two elements are present and in the same set.
+func (ds *DisjointSets[T]) Connected(a, b T) bool {
+	ra, ok := ds.Find(a)
+	if !ok {
+		return false
+	}
+	rb, ok := ds.Find(b)
+	return ok && ra == rb
+}
+
+// Partitions returns the disjoint sets as a slice of Sets, in no particular
+// order.
+func (ds *DisjointSets[T]) Partitions() []Set[T] {
+	index := make(map[T]int, ds.components)
+	partitions := make([]Set[T], 0, ds.components)
+	for s := range ds.parent {
+		r := ds.find(s)
+		i, ok := index[r]
+		if !ok {
+			i = len(partitions)
+			index[r] = i
+			partitions = append(partitions, New[T]())
+		}
+		partitions[i][s] = struct{}{}
+	}
+	return partitions
+}
+
+// find returns the representative of a present element, pointing every
+// element on the way directly to it.
+func (ds *DisjointSets[T]) find(s T) T {
+	root := s
+	for p := ds.parent[root]; p != root; p = ds.parent[root] {
+		root = p
+	}
+	for s != root {
+		next := ds.parent[s]
+		ds.parent[s] = root
+		s = next
+	}
+	return root
+}
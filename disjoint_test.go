@@ -0,0 +1,130 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisjointSetsBasic(t *testing.T) {
+	ds := NewDisjointSetsFromSlice([]string{"a", "b", "c", "d"})
+	require.Equal(t, 4, ds.Len())
+	require.Equal(t, 4, ds.Components())
+	require.False(t, ds.MakeSet("a"))
+	require.True(t, ds.Contains("a"))
+	require.False(t, ds.Contains("x"))
+
+	r, ok := ds.Find("a")
+	require.True(t, ok)
+	require.Equal(t, "a", r)
+	_, ok = ds.Find("x")
+	require.False(t, ok)
+
+	require.True(t, ds.Union("a", "b"))
+	require.False(t, ds.Union("b", "a"))
+	require.True(t, ds.Union("c", "e"))
+	require.Equal(t, 5, ds.Len())
+	require.Equal(t, 3, ds.Components())
+
+	require.True(t, ds.Connected("a", "b"))
+	require.True(t, ds.Connected("e", "c"))
+	require.True(t, ds.Connected("d", "d"))
+	require.False(t, ds.Connected("a", "c"))
+	require.False(t, ds.Connected("a", "x"))
+	require.False(t, ds.Connected("x", "x"))
+
+	ra, _ := ds.Find("a")
+	rb, _ := ds.Find("b")
+	require.Equal(t, ra, rb)
+
+	require.True(t, ds.Union("b", "e"))
+	require.True(t, ds.Connected("a", "c"))
+	require.Equal(t, 2, ds.Components())
+	require.ElementsMatch(t, []Set[string]{
+		NewFromSlice([]string{"a", "b", "c", "e"}),
+		NewFromSlice([]string{"d"}),
+	}, ds.Partitions())
+}
+
+func TestDisjointSetsEmpty(t *testing.T) {
+	ds := NewDisjointSets[int]()
+	require.Equal(t, 0, ds.Len())
+	require.Equal(t, 0, ds.Components())
+	require.Equal(t, []Set[int]{}, ds.Partitions())
+}
+
+func TestDisjointSetsMatchesNaive(t *testing.T) {
+	const n = 200
+	rnd := rand.New(rand.NewSource(1))
+	ds := NewDisjointSets[int]()
+	// naive labels every element with the id of its component
+	naive := make(map[int]int)
+	for i := 0; i < 300; i++ {
+		a, b := rnd.Intn(n), rnd.Intn(n)
+		for _, s := range []int{a, b} {
+			if _, ok := naive[s]; !ok {
+				naive[s] = s
+			}
+		}
+		la, lb := naive[a], naive[b]
+		require.Equal(t, la != lb, ds.Union(a, b))
+		for s, l := range naive {
+			if l == lb {
+				naive[s] = la
+			}
+		}
+	}
+
+	labels := New[int]()
+	for _, l := range naive {
+		labels.Add(l)
+	}
+	require.Equal(t, len(naive), ds.Len())
+	require.Equal(t, len(labels), ds.Components())
+	for a := 0; a < n; a++ {
+		for b := 0; b < n; b++ {
+			_, okA := naive[a]
+			_, okB := naive[b]
+			require.Equal(t, okA && okB && naive[a] == naive[b], ds.Connected(a, b))
+		}
+	}
+
+	partitions := ds.Partitions()
+	require.Len(t, partitions, ds.Components())
+	seen := New[int]()
+	for _, p := range partitions {
+		require.NotEmpty(t, p)
+		for s := range p {
+			require.False(t, seen.Contains(s))
+			seen.Add(s)
+			require.True(t, ds.Connected(s, p.ToSlice()[0]))
+		}
+	}
+	require.Equal(t, len(naive), len(seen))
+}
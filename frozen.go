@@ -0,0 +1,109 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import "iter"
+
+// Frozen is a read-only view of a Set. It only exposes non-mutating methods,
+// so a Frozen can be handed to code that must not change the Set. Create one
+// with Set.Freeze or Set.FreezeView. Binary operations take Frozen operands,
+// pass a Set through its zero-copy FreezeView, and return a new Set owned by
+// the caller. The zero value is an empty Frozen.
+type Frozen[T comparable] struct {
+	set Set[T]
+}
+
+// Freeze returns a Frozen holding a copy of a Set. Later changes to the Set
+// are not visible through the Frozen.
+func (set Set[T]) Freeze() Frozen[T] {
+	return Frozen[T]{set: set.clone()}
+}
+
+// FreezeView returns a Frozen sharing the storage of a Set, without copying
+// it. Later changes to the Set are visible through the Frozen, so only use it
+// when the Set is no longer modified, or when that is intended.
+func (set Set[T]) FreezeView() Frozen[T] {
+	return Frozen[T]{set: set}
+}
+
+// Len returns the number of elements in a Frozen.
+func (f Frozen[T]) Len() int {
+	return len(f.set)
+}
+
+// Contains returns true if a Frozen contains an element.
+func (f Frozen[T]) Contains(s T) bool {
+	return f.set.Contains(s)
+}
+
+// All returns an iterator over the elements of a Frozen, in no particular
+// order.
+func (f Frozen[T]) All() iter.Seq[T] {
+	return f.set.All()
+}
+
+// ToSlice returns an unordered slice of elements from a Frozen.
+func (f Frozen[T]) ToSlice() []T {
+	return f.set.ToSlice()
+}
+
+// Thaw returns a mutable copy of a Frozen as a Set.
+func (f Frozen[T]) Thaw() Set[T] {
+	return f.set.clone()
+}
+
+// Equals returns true if two Frozen are equal. Compare with a Set through its
+// zero-copy view: f.Equals(set.FreezeView()).
+func (f Frozen[T]) Equals(other Frozen[T]) bool {
+	return f.set.Equals(other.set)
+}
+
+// IsSubsetOf returns true if a Frozen is a subset of another Frozen (they can
+// be equal).
+func (f Frozen[T]) IsSubsetOf(other Frozen[T]) bool {
+	return f.set.IsSubsetOf(other.set)
+}
+
+// IsProperSubsetOf returns true if a Frozen is a proper subset of another
+// Frozen (they cannot be equal).
+func (f Frozen[T]) IsProperSubsetOf(other Frozen[T]) bool {
+	return f.set.IsProperSubsetOf(other.set)
+}
+
+// Union returns the union of two Frozen as new Set.
+func (f Frozen[T]) Union(other Frozen[T]) Set[T] {
+	return f.set.Union(other.set)
+}
+
+// Intersection returns the intersection of two Frozen as new Set.
+func (f Frozen[T]) Intersection(other Frozen[T]) Set[T] {
+	return f.set.Intersection(other.set)
+}
+
+// Difference returns the difference of two Frozen as new Set.
+func (f Frozen[T]) Difference(other Frozen[T]) Set[T] {
+	return f.set.Difference(other.set)
+}
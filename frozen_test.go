@@ -0,0 +1,135 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrozenFreeze(t *testing.T) {
+	set := NewFromSlice([]string{"a", "b"})
+	frozen := set.Freeze()
+	view := set.FreezeView()
+
+	set.Add("c")
+	require.Equal(t, 2, frozen.Len())
+	require.False(t, frozen.Contains("c"))
+	require.Equal(t, 3, view.Len())
+	require.True(t, view.Contains("c"))
+
+	thawed := frozen.Thaw()
+	thawed.Add("x")
+	require.False(t, frozen.Contains("x"))
+}
+
+func TestFrozenZeroValue(t *testing.T) {
+	var f Frozen[int]
+	require.Equal(t, 0, f.Len())
+	require.False(t, f.Contains(1))
+	require.Empty(t, f.ToSlice())
+	require.True(t, f.Equals(New[int]().FreezeView()))
+	require.Equal(t, Set[int]{1: struct{}{}}, f.Union(NewFromSlice([]int{1}).FreezeView()))
+}
+
+func TestFrozenIteration(t *testing.T) {
+	frozen := NewFromSlice([]int{3, 1, 2}).Freeze()
+	require.Equal(t, []int{1, 2, 3}, slices.Sorted(frozen.All()))
+	actual := frozen.ToSlice()
+	slices.Sort(actual)
+	require.Equal(t, []int{1, 2, 3}, actual)
+}
+
+func TestFrozenOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          Set[string]
+		other        Set[string]
+		union        Set[string]
+		intersection Set[string]
+		difference   Set[string]
+		equals       bool
+		subset       bool
+		properSubset bool
+	}{
+		{
+			name:         "empty sets",
+			set:          Set[string]{},
+			other:        Set[string]{},
+			union:        Set[string]{},
+			intersection: Set[string]{},
+			difference:   Set[string]{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "proper subset",
+			set:          NewFromSlice([]string{"a"}),
+			other:        NewFromSlice([]string{"a", "b"}),
+			union:        NewFromSlice([]string{"a", "b"}),
+			intersection: NewFromSlice([]string{"a"}),
+			difference:   Set[string]{},
+			equals:       false,
+			subset:       true,
+			properSubset: true,
+		},
+		{
+			name:         "partial overlap",
+			set:          NewFromSlice([]string{"a", "b", "c"}),
+			other:        NewFromSlice([]string{"b", "c", "d"}),
+			union:        NewFromSlice([]string{"a", "b", "c", "d"}),
+			intersection: NewFromSlice([]string{"b", "c"}),
+			difference:   NewFromSlice([]string{"a"}),
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			frozen := c.set.Freeze()
+			other := c.other.FreezeView()
+			require.Equal(t, c.union, frozen.Union(other))
+			require.Equal(t, c.intersection, frozen.Intersection(other))
+			require.Equal(t, c.difference, frozen.Difference(other))
+			require.Equal(t, c.equals, frozen.Equals(other))
+			require.Equal(t, c.subset, frozen.IsSubsetOf(other))
+			require.Equal(t, c.properSubset, frozen.IsProperSubsetOf(other))
+		})
+	}
+}
+
+func TestFrozenResultsAreIndependent(t *testing.T) {
+	set := NewFromSlice([]int{1, 2})
+	frozen := set.FreezeView()
+	union := frozen.Union(New[int]().FreezeView())
+	union.Add(3)
+	require.False(t, frozen.Contains(3))
+	require.False(t, set.Contains(3))
+}
@@ -0,0 +1,171 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// stableHash returns a 128 bit hash of v, split in two halves. Unlike the
+// seeded hashers of ShardedSet it is the same in every process, so
+// probabilistic structures hashed with it can be serialized and merged.
+// Pointers still hash by address.
+func stableHash(v any) (uint64, uint64) {
+	h := fnv.New128a()
+	h.Write(appendStable(make([]byte, 0, 16), v))
+	var sum [16]byte
+	h.Sum(sum[:0])
+	return mix64(binary.LittleEndian.Uint64(sum[:8])), mix64(binary.LittleEndian.Uint64(sum[8:]))
+}
+
+// appendStable appends a canonical encoding of v to buf. Equal values of the
+// same type have the same encoding: floats and complex numbers encode -0 as
+// +0, structs and arrays their fields in order, interfaces their dynamic
+// type and value, and pointers and channels their address.
+func appendStable(buf []byte, v any) []byte {
+	switch v := v.(type) {
+	case string:
+		return append(buf, v...)
+	case int:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case int8:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case int16:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case int32:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case int64:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case uint:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case uint8:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case uint16:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case uint32:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case uint64:
+		return binary.LittleEndian.AppendUint64(buf, v)
+	case uintptr:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	case float32:
+		return appendStableFloat(buf, float64(v))
+	case float64:
+		return appendStableFloat(buf, v)
+	case bool:
+		return appendStableBool(buf, v)
+	default:
+		return appendStableValue(buf, reflect.ValueOf(v))
+	}
+}
+
+// appendStableValue appends the canonical encoding of a value of any other
+// type. Strings nested in it are prefixed with their length, so that
+// neighbouring fields cannot run into each other.
+func appendStableValue(buf []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return append(buf, 0)
+	case reflect.Bool:
+		return appendStableBool(buf, v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.LittleEndian.AppendUint64(buf, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.LittleEndian.AppendUint64(buf, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return appendStableFloat(buf, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return appendStableFloat(appendStableFloat(buf, real(c)), imag(c))
+	case reflect.String:
+		return appendStableString(buf, v.String())
+	case reflect.Array:
+		for i := range v.Len() {
+			buf = appendStableValue(buf, v.Index(i))
+		}
+		return buf
+	case reflect.Slice:
+		buf = binary.AppendUvarint(buf, uint64(v.Len()))
+		for i := range v.Len() {
+			buf = appendStableValue(buf, v.Index(i))
+		}
+		return buf
+	case reflect.Struct:
+		t := v.Type()
+		for i := range v.NumField() {
+			// blank fields are ignored by ==
+			if t.Field(i).Name != "_" {
+				buf = appendStableValue(buf, v.Field(i))
+			}
+		}
+		return buf
+	case reflect.Interface:
+		if v.IsNil() {
+			return append(buf, 0)
+		}
+		t := v.Elem().Type()
+		buf = append(buf, 1)
+		buf = appendStableString(buf, t.PkgPath())
+		buf = appendStableString(buf, t.String())
+		return appendStableValue(buf, v.Elem())
+	default:
+		// pointers, channels, maps and functions
+		return binary.LittleEndian.AppendUint64(buf, uint64(v.Pointer()))
+	}
+}
+
+func appendStableBool(buf []byte, v bool) []byte {
+	if v {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+func appendStableFloat(buf []byte, v float64) []byte {
+	if v == 0 {
+		// +0 and -0 are equal, so they must hash the same
+		v = 0
+	}
+	return binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+}
+
+func appendStableString(buf []byte, v string) []byte {
+	return append(binary.AppendUvarint(buf, uint64(len(v))), v...)
+}
+
+// mix64 is the splitmix64 finalizer, spreading every input bit over the
+// whole output.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,111 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// goStringer has a GoString method, which must not affect its encoding.
+type goStringer struct{ N int }
+
+func (g goStringer) GoString() string { return "same" }
+
+func TestAppendStableEqualValues(t *testing.T) {
+	type fp struct {
+		Name string
+		X    float64
+	}
+	type nested struct {
+		P fp
+		C complex128
+		A [2]float32
+		_ int
+		V any
+	}
+	zero := 0.0
+	negZero := -zero
+	ch := make(chan int)
+	cases := []struct {
+		name string
+		a, b any
+	}{
+		{name: "struct", a: fp{"a", negZero}, b: fp{"a", zero}},
+		{name: "array", a: [2]float64{1, negZero}, b: [2]float64{1, zero}},
+		{name: "complex", a: complex(negZero, negZero), b: complex(zero, zero)},
+		{
+			name: "nested",
+			a:    nested{P: fp{X: negZero}, C: complex(1, negZero), A: [2]float32{float32(negZero)}, V: negZero},
+			b:    nested{P: fp{X: zero}, C: complex(1, zero), A: [2]float32{0}, V: zero},
+		},
+		{name: "nil interface", a: struct{ V any }{}, b: struct{ V any }{nil}},
+		{name: "channel", a: struct{ C chan int }{ch}, b: struct{ C chan int }{ch}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.True(t, c.a == c.b)
+			require.Equal(t, appendStable(nil, c.a), appendStable(nil, c.b))
+			ha1, ha2 := stableHash(c.a)
+			hb1, hb2 := stableHash(c.b)
+			require.Equal(t, ha1, hb1)
+			require.Equal(t, ha2, hb2)
+		})
+	}
+}
+
+func TestAppendStableDistinctValues(t *testing.T) {
+	type pair struct{ A, B string }
+	type boxed struct{ V any }
+	cases := []struct {
+		name string
+		a, b any
+	}{
+		{name: "strings", a: pair{"a", "bc"}, b: pair{"ab", "c"}},
+		{name: "dynamic types", a: boxed{1}, b: boxed{int8(1)}},
+		{name: "nil and zero", a: boxed{nil}, b: boxed{0}},
+		{name: "floats", a: struct{ X float64 }{1}, b: struct{ X float64 }{math.Nextafter(1, 2)}},
+		{name: "GoString", a: goStringer{1}, b: goStringer{2}},
+		{name: "pointers", a: struct{ P *int }{new(int)}, b: struct{ P *int }{new(int)}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.False(t, c.a == c.b)
+			require.NotEqual(t, appendStable(nil, c.a), appendStable(nil, c.b))
+		})
+	}
+}
+
+func TestShardedSetCompositeZeroKeys(t *testing.T) {
+	type fp struct{ X float64 }
+	zero := 0.0
+	ss := NewShardedFromSlice(8, []fp{{-zero}})
+	require.True(t, ss.Contains(fp{zero}))
+	ss.Add(fp{zero})
+	require.Equal(t, 1, ss.Len())
+}
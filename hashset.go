@@ -0,0 +1,298 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/maphash"
+	"iter"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Hasher defines element identity for a HashSet. Equal elements must have
+// the same hash.
+type Hasher[T any] interface {
+	Hash(s T) uint64
+	Equal(a, b T) bool
+}
+
+// HashSet is a generic, not threadsafe set data structure for element types
+// that are not comparable, or that should be compared by something other
+// than ==. Elements are grouped in buckets by hash and told apart by Equal.
+type HashSet[T any] struct {
+	buckets map[uint64][]T
+	size    int
+	hasher  Hasher[T]
+}
+
+// NewHashSet creates a new HashSet using a Hasher.
+func NewHashSet[T any](hasher Hasher[T]) *HashSet[T] {
+	return &HashSet[T]{buckets: make(map[uint64][]T), hasher: hasher}
+}
+
+// NewHashSetFromSlice creates a new HashSet using a Hasher from a slice. Of
+// equal elements only the first one is kept.
+func NewHashSetFromSlice[T any](hasher Hasher[T], slice []T) *HashSet[T] {
+	hs := NewHashSet(hasher)
+	hs.AddAll(slice)
+	return hs
+}
+
+// Len returns the number of elements in a HashSet.
+func (hs *HashSet[T]) Len() int {
+	return hs.size
+}
+
+// ToSlice returns an unordered slice of elements from a HashSet.
+func (hs *HashSet[T]) ToSlice() []T {
+	slice := make([]T, 0, hs.size)
+	for _, bucket := range hs.buckets {
+		slice = append(slice, bucket...)
+	}
+	return slice
+}
+
+// All returns an iterator over the elements of a HashSet, in no particular
+// order.
+func (hs *HashSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, bucket := range hs.buckets {
+			for _, s := range bucket {
+				if !yield(s) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Contains returns true if a HashSet contains an element equal to s.
+func (hs *HashSet[T]) Contains(s T) bool {
+	_, ok := hs.index(s)
+	return ok
+}
+
+// Add adds an element to a HashSet. It does nothing if an equal element is
+// already present.
+func (hs *HashSet[T]) Add(s T) {
+	h := hs.hasher.Hash(s)
+	for _, t := range hs.buckets[h] {
+		if hs.hasher.Equal(s, t) {
+			return
+		}
+	}
+	hs.buckets[h] = append(hs.buckets[h], s)
+	hs.size++
+}
+
+// Remove removes the element equal to s from a HashSet.
+func (hs *HashSet[T]) Remove(s T) {
+	h := hs.hasher.Hash(s)
+	bucket := hs.buckets[h]
+	for i, t := range bucket {
+		if hs.hasher.Equal(s, t) {
+			if len(bucket) == 1 {
+				delete(hs.buckets, h)
+			} else {
+				last := len(bucket) - 1
+				bucket[i] = bucket[last]
+				var zero T
+				bucket[last] = zero
+				hs.buckets[h] = bucket[:last]
+			}
+			hs.size--
+			return
+		}
+	}
+}
+
+// AddAll adds a slice of elements to a HashSet.
+func (hs *HashSet[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		hs.Add(s)
+	}
+}
+
+// RemoveAll removes a slice of elements from a HashSet.
+func (hs *HashSet[T]) RemoveAll(slice []T) {
+	for _, s := range slice {
+		hs.Remove(s)
+	}
+}
+
+// Equals returns true if two HashSets hold equal elements, as told by the
+// Hasher of the receiver.
+func (hs *HashSet[T]) Equals(other *HashSet[T]) bool {
+	return hs.size == other.size && hs.IsSubsetOf(other)
+}
+
+// IsSubsetOf returns true if a HashSet is a subset of another HashSet (they
+// can be equal).
+func (hs *HashSet[T]) IsSubsetOf(other *HashSet[T]) bool {
+	if hs.size > other.size {
+		return false
+	}
+	for s := range hs.All() {
+		if !other.Contains(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProperSubsetOf returns true if a HashSet is a proper subset of another
+// HashSet (they cannot be equal).
+func (hs *HashSet[T]) IsProperSubsetOf(other *HashSet[T]) bool {
+	return hs.size < other.size && hs.IsSubsetOf(other)
+}
+
+// Union returns the union of two HashSets as new HashSet using the Hasher of
+// the receiver.
+func (hs *HashSet[T]) Union(other *HashSet[T]) *HashSet[T] {
+	result := NewHashSet(hs.hasher)
+	for s := range hs.All() {
+		result.Add(s)
+	}
+	for s := range other.All() {
+		result.Add(s)
+	}
+	return result
+}
+
+// Intersection returns the intersection of two HashSets as new HashSet using
+// the Hasher of the receiver.
+func (hs *HashSet[T]) Intersection(other *HashSet[T]) *HashSet[T] {
+	result := NewHashSet(hs.hasher)
+	for s := range hs.All() {
+		if other.Contains(s) {
+			result.Add(s)
+		}
+	}
+	return result
+}
+
+// Difference returns the difference of two HashSets as new HashSet using the
+// Hasher of the receiver.
+func (hs *HashSet[T]) Difference(other *HashSet[T]) *HashSet[T] {
+	result := NewHashSet(hs.hasher)
+	for s := range hs.All() {
+		if !other.Contains(s) {
+			result.Add(s)
+		}
+	}
+	return result
+}
+
+func (hs *HashSet[T]) index(s T) (int, bool) {
+	for i, t := range hs.buckets[hs.hasher.Hash(s)] {
+		if hs.hasher.Equal(s, t) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+type bytesHasher struct {
+	seed maphash.Seed
+}
+
+// NewBytesHasher returns a Hasher comparing byte slices by content. A nil
+// slice equals an empty one.
+func NewBytesHasher() Hasher[[]byte] {
+	return bytesHasher{seed: maphash.MakeSeed()}
+}
+
+func (h bytesHasher) Hash(s []byte) uint64 {
+	return maphash.Bytes(h.seed, s)
+}
+
+func (h bytesHasher) Equal(a, b []byte) bool {
+	return bytes.Equal(a, b)
+}
+
+type foldHasher struct {
+	seed maphash.Seed
+}
+
+// NewFoldHasher returns a Hasher comparing strings under Unicode case
+// folding, like strings.EqualFold.
+func NewFoldHasher() Hasher[string] {
+	return foldHasher{seed: maphash.MakeSeed()}
+}
+
+func (h foldHasher) Hash(s string) uint64 {
+	var mh maphash.Hash
+	mh.SetSeed(h.seed)
+	var buf [utf8.UTFMax]byte
+	for _, r := range s {
+		n := utf8.EncodeRune(buf[:], foldRune(r))
+		mh.Write(buf[:n])
+	}
+	return mh.Sum64()
+}
+
+func (h foldHasher) Equal(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
+
+// foldRune returns the smallest rune equivalent to r under simple case
+// folding, so all runes strings.EqualFold considers equal map to the same one.
+func foldRune(r rune) rune {
+	least := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		least = min(least, f)
+	}
+	return least
+}
+
+type sliceHasher[E comparable] struct {
+	seed maphash.Seed
+}
+
+// NewSliceHasher returns a Hasher comparing slices of comparable element by
+// element. A nil slice equals an empty one.
+func NewSliceHasher[E comparable]() Hasher[[]E] {
+	return sliceHasher[E]{seed: maphash.MakeSeed()}
+}
+
+func (h sliceHasher[E]) Hash(s []E) uint64 {
+	var mh maphash.Hash
+	mh.SetSeed(h.seed)
+	var b [8]byte
+	for _, e := range s {
+		binary.LittleEndian.PutUint64(b[:], hashAny(h.seed, e))
+		mh.Write(b[:])
+	}
+	return mh.Sum64()
+}
+
+func (h sliceHasher[E]) Equal(a, b []E) bool {
+	return slices.Equal(a, b)
+}
@@ -0,0 +1,213 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// collidingHasher puts every int in the same bucket, to exercise collisions.
+type collidingHasher struct{}
+
+func (collidingHasher) Hash(int) uint64     { return 42 }
+func (collidingHasher) Equal(a, b int) bool { return a == b }
+
+type account struct {
+	ID    int
+	Names []string
+}
+
+// accountHasher identifies accounts by ID only.
+type accountHasher struct{}
+
+func (accountHasher) Hash(a account) uint64   { return uint64(a.ID) }
+func (accountHasher) Equal(a, b account) bool { return a.ID == b.ID }
+
+func TestHashSetBasic(t *testing.T) {
+	hs := NewHashSetFromSlice[int](collidingHasher{}, []int{1, 2, 3, 2})
+	require.Equal(t, 3, hs.Len())
+	require.True(t, hs.Contains(2))
+	require.False(t, hs.Contains(4))
+
+	hs.Add(4)
+	hs.Remove(1)
+	hs.Remove(9)
+	hs.AddAll([]int{5, 6})
+	hs.RemoveAll([]int{2, 6})
+	actual := hs.ToSlice()
+	slices.Sort(actual)
+	require.Equal(t, []int{3, 4, 5}, actual)
+	require.Equal(t, NewFromSlice([]int{3, 4, 5}), Collect(hs.All()))
+	require.Len(t, firstN(hs.All(), 2), 2)
+
+	hs.RemoveAll([]int{3, 4, 5})
+	require.Equal(t, 0, hs.Len())
+	require.Empty(t, hs.buckets)
+}
+
+func TestHashSetStructByID(t *testing.T) {
+	hs := NewHashSet[account](accountHasher{})
+	hs.Add(account{ID: 1, Names: []string{"a"}})
+	hs.Add(account{ID: 1, Names: []string{"b"}})
+	hs.Add(account{ID: 2})
+	require.Equal(t, 2, hs.Len())
+	require.True(t, hs.Contains(account{ID: 1}))
+
+	// the first element added is kept
+	for a := range hs.All() {
+		if a.ID == 1 {
+			require.Equal(t, []string{"a"}, a.Names)
+		}
+	}
+}
+
+func TestHashSetHashers(t *testing.T) {
+	t.Run("bytes", func(t *testing.T) {
+		h := NewBytesHasher()
+		hs := NewHashSetFromSlice(h, [][]byte{[]byte("ab"), []byte("ab"), nil, {}, []byte("b")})
+		require.Equal(t, 3, hs.Len())
+		require.True(t, hs.Contains([]byte{'a', 'b'}))
+		require.True(t, hs.Contains([]byte{}))
+		require.Equal(t, h.Hash(nil), h.Hash([]byte{}))
+	})
+	t.Run("fold", func(t *testing.T) {
+		h := NewFoldHasher()
+		pairs := [][2]string{{"Go", "gO"}, {"STRASSE", "strasse"}, {"Σίσυφος", "ΣΊΣΥΦΟΣ"}, {"k", "K"}, {"ſ", "S"}}
+		for _, p := range pairs {
+			require.True(t, strings.EqualFold(p[0], p[1]))
+			require.True(t, h.Equal(p[0], p[1]))
+			require.Equal(t, h.Hash(p[0]), h.Hash(p[1]), "%q and %q", p[0], p[1])
+		}
+		hs := NewHashSetFromSlice(h, []string{"Hello", "HELLO", "world"})
+		require.Equal(t, 2, hs.Len())
+		require.True(t, hs.Contains("WORLD"))
+		require.False(t, hs.Contains("hell"))
+	})
+	t.Run("slice", func(t *testing.T) {
+		h := NewSliceHasher[int]()
+		hs := NewHashSetFromSlice(h, [][]int{{1, 2}, {1, 2}, {2, 1}, nil, {}})
+		require.Equal(t, 3, hs.Len())
+		require.True(t, hs.Contains([]int{2, 1}))
+		require.False(t, hs.Contains([]int{1}))
+		require.NotEqual(t, h.Hash([]int{1, 2}), h.Hash([]int{2, 1}))
+	})
+	t.Run("slice of structs", func(t *testing.T) {
+		type fp struct{ X float64 }
+		zero := 0.0
+		h := NewSliceHasher[fp]()
+		require.True(t, slices.Equal([]fp{{-zero}}, []fp{{zero}}))
+		require.True(t, h.Equal([]fp{{-zero}}, []fp{{zero}}))
+		require.Equal(t, h.Hash([]fp{{-zero}}), h.Hash([]fp{{zero}}))
+		hs := NewHashSetFromSlice(h, [][]fp{{{-zero}}, {{zero}}})
+		require.Equal(t, 1, hs.Len())
+		require.True(t, hs.Contains([]fp{{zero}}))
+	})
+}
+
+func TestHashSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          []string
+		other        []string
+		union        []string
+		intersection []string
+		difference   []string
+		equals       bool
+		subset       bool
+		properSubset bool
+	}{
+		{
+			name:         "empty sets",
+			set:          []string{},
+			other:        []string{},
+			union:        []string{},
+			intersection: []string{},
+			difference:   []string{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "equal sets ignoring case",
+			set:          []string{"a", "b"},
+			other:        []string{"B", "A"},
+			union:        []string{"a", "b"},
+			intersection: []string{"a", "b"},
+			difference:   []string{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "proper subset",
+			set:          []string{"a"},
+			other:        []string{"A", "b"},
+			union:        []string{"a", "b"},
+			intersection: []string{"a"},
+			difference:   []string{},
+			equals:       false,
+			subset:       true,
+			properSubset: true,
+		},
+		{
+			name:         "partial overlap",
+			set:          []string{"a", "b", "c"},
+			other:        []string{"C", "d"},
+			union:        []string{"a", "b", "c", "d"},
+			intersection: []string{"c"},
+			difference:   []string{"a", "b"},
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+	}
+	h := NewFoldHasher()
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			set := NewHashSetFromSlice(h, c.set)
+			other := NewHashSetFromSlice(h, c.other)
+			for _, op := range []struct {
+				actual   *HashSet[string]
+				expected []string
+			}{
+				{set.Union(other), c.union},
+				{set.Intersection(other), c.intersection},
+				{set.Difference(other), c.difference},
+			} {
+				actual := op.actual.ToSlice()
+				slices.Sort(actual)
+				require.Equal(t, op.expected, actual)
+			}
+			require.Equal(t, c.equals, set.Equals(other))
+			require.Equal(t, c.subset, set.IsSubsetOf(other))
+			require.Equal(t, c.properSubset, set.IsProperSubsetOf(other))
+		})
+	}
+}
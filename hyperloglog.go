@@ -0,0 +1,243 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// Precision bounds of a HyperLogLog. The relative standard error of Count is
+// about 1.04 / sqrt(2^precision).
+const (
+	MinHLLPrecision     = 4
+	MaxHLLPrecision     = 18
+	DefaultHLLPrecision = 14
+)
+
+// hllSparsePrecision is the number of index bits of the sparse
+// representation. Its estimate is nearly exact until it is converted.
+const hllSparsePrecision = 25
+
+// HyperLogLog is a generic, not threadsafe sketch estimating the number of
+// distinct elements added to it in a fixed amount of memory. Small sketches
+// use a sparse representation, converted to 2^precision registers as they
+// grow.
+type HyperLogLog[T comparable] struct {
+	precision uint8
+	sparse    map[uint32]uint8 // leading zeros + 1 by 25 bit index
+	registers []uint8          // leading zeros + 1 by index, once dense
+}
+
+// NewHyperLogLog creates a new HyperLogLog with 2^precision registers.
+// Precisions out of range are replaced by DefaultHLLPrecision.
+func NewHyperLogLog[T comparable](precision int) *HyperLogLog[T] {
+	if precision < MinHLLPrecision || precision > MaxHLLPrecision {
+		precision = DefaultHLLPrecision
+	}
+	return &HyperLogLog[T]{precision: uint8(precision), sparse: make(map[uint32]uint8)}
+}
+
+// Precision returns the precision of a HyperLogLog.
+func (hll *HyperLogLog[T]) Precision() int {
+	return int(hll.precision)
+}
+
+// Add adds an element to a HyperLogLog.
+func (hll *HyperLogLog[T]) Add(s T) {
+	x, _ := stableHash(s)
+	if hll.registers != nil {
+		hll.addDense(x)
+		return
+	}
+	idx := uint32(x >> (64 - hllSparsePrecision))
+	rho := uint8(bits.LeadingZeros64(x<<hllSparsePrecision|1<<(hllSparsePrecision-1))) + 1
+	if rho > hll.sparse[idx] {
+		hll.sparse[idx] = rho
+		hll.maybeConvert()
+	}
+}
+
+// AddAll adds a slice of elements to a HyperLogLog.
+func (hll *HyperLogLog[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		hll.Add(s)
+	}
+}
+
+// Count returns the estimated number of distinct elements added to a
+// HyperLogLog.
+func (hll *HyperLogLog[T]) Count() int {
+	if hll.registers == nil {
+		// linear counting over the sparse registers
+		m := float64(uint64(1) << hllSparsePrecision)
+		return int(math.Round(m * math.Log(m/(m-float64(len(hll.sparse))))))
+	}
+	return int(math.Round(hllEstimate(hll.registers, int(hll.precision))))
+}
+
+// Merge adds the elements counted by another HyperLogLog to a HyperLogLog,
+// so that it counts their union. It returns ErrIncompatible if their
+// precisions differ.
+func (hll *HyperLogLog[T]) Merge(other *HyperLogLog[T]) error {
+	if hll.precision != other.precision {
+		return fmt.Errorf("%w: hyperloglog precisions %d and %d", ErrIncompatible, hll.precision, other.precision)
+	}
+	if hll.registers == nil && other.registers == nil {
+		for idx, rho := range other.sparse {
+			hll.sparse[idx] = max(hll.sparse[idx], rho)
+		}
+		hll.maybeConvert()
+		return nil
+	}
+	hll.toDense()
+	if other.registers != nil {
+		for i, rho := range other.registers {
+			hll.registers[i] = max(hll.registers[i], rho)
+		}
+		return nil
+	}
+	for idx, rho := range other.sparse {
+		hll.addSparseEntry(idx, rho)
+	}
+	return nil
+}
+
+// IntersectionCount returns the estimated number of distinct elements added
+// to both HyperLogLogs, by inclusion-exclusion. Its error is relative to the
+// size of the union, so it is poor for small intersections of large sets. It
+// returns ErrIncompatible if their precisions differ.
+func (hll *HyperLogLog[T]) IntersectionCount(other *HyperLogLog[T]) (int, error) {
+	union := hll.clone()
+	if err := union.Merge(other); err != nil {
+		return 0, err
+	}
+	return max(hll.Count()+other.Count()-union.Count(), 0), nil
+}
+
+func (hll *HyperLogLog[T]) clone() *HyperLogLog[T] {
+	result := &HyperLogLog[T]{precision: hll.precision}
+	if hll.registers != nil {
+		result.registers = append([]uint8(nil), hll.registers...)
+		return result
+	}
+	result.sparse = make(map[uint32]uint8, len(hll.sparse))
+	for idx, rho := range hll.sparse {
+		result.sparse[idx] = rho
+	}
+	return result
+}
+
+func (hll *HyperLogLog[T]) addDense(x uint64) {
+	p := hll.precision
+	idx := x >> (64 - p)
+	rho := uint8(bits.LeadingZeros64(x<<p|1<<(p-1))) + 1
+	hll.registers[idx] = max(hll.registers[idx], rho)
+}
+
+// addSparseEntry folds a sparse register into the dense registers. The index
+// bits beyond the precision are the first bits counted by the dense rho.
+func (hll *HyperLogLog[T]) addSparseEntry(idx uint32, rho uint8) {
+	extra := hllSparsePrecision - hll.precision
+	low := idx & (1<<extra - 1)
+	if low != 0 {
+		rho = uint8(bits.LeadingZeros32(low<<(32-extra))) + 1
+	} else {
+		rho += extra
+	}
+	i := idx >> extra
+	hll.registers[i] = max(hll.registers[i], rho)
+}
+
+// maybeConvert switches to dense registers once the sparse ones would take
+// more memory.
+func (hll *HyperLogLog[T]) maybeConvert() {
+	if len(hll.sparse) > (1<<hll.precision)/8 {
+		hll.toDense()
+	}
+}
+
+func (hll *HyperLogLog[T]) toDense() {
+	if hll.registers != nil {
+		return
+	}
+	hll.registers = make([]uint8, 1<<hll.precision)
+	for idx, rho := range hll.sparse {
+		hll.addSparseEntry(idx, rho)
+	}
+	hll.sparse = nil
+}
+
+// hllEstimate implements the improved raw estimator of Ertl, "New
+// cardinality estimation algorithms for HyperLogLog sketches" (2017), which
+// needs no empirical bias correction.
+func hllEstimate(registers []uint8, p int) float64 {
+	q := 64 - p
+	counts := make([]float64, q+2)
+	for _, rho := range registers {
+		counts[rho]++
+	}
+	m := float64(len(registers))
+	z := m * hllTau(1-counts[q+1]/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + counts[k])
+	}
+	z += m * hllSigma(counts[0]/m)
+	return m * m / (2 * math.Ln2 * z)
+}
+
+func hllSigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		prev := z
+		z += x * y
+		y += y
+		if z == prev {
+			return z
+		}
+	}
+}
+
+func hllTau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		prev := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if z == prev {
+			return z / 3
+		}
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// requireCountNear checks an estimate against an exact count, allowing four
+// standard errors of a HyperLogLog with precision p.
+func requireCountNear(t *testing.T, exact, estimate, p int) {
+	t.Helper()
+	tolerance := 4 * 1.04 / math.Sqrt(float64(uint64(1)<<p)) * float64(exact)
+	require.InDelta(t, exact, estimate, max(tolerance, 1), "exact %d, estimate %d", exact, estimate)
+}
+
+func TestHyperLogLogPrecision(t *testing.T) {
+	require.Equal(t, 10, NewHyperLogLog[int](10).Precision())
+	require.Equal(t, DefaultHLLPrecision, NewHyperLogLog[int](MinHLLPrecision-1).Precision())
+	require.Equal(t, DefaultHLLPrecision, NewHyperLogLog[int](MaxHLLPrecision+1).Precision())
+}
+
+func TestHyperLogLogCount(t *testing.T) {
+	for _, p := range []int{MinHLLPrecision, 10, DefaultHLLPrecision} {
+		t.Run(fmt.Sprint(p), func(t *testing.T) {
+			hll := NewHyperLogLog[string](p)
+			require.Equal(t, 0, hll.Count())
+			exact := New[string]()
+			next := 0
+			for _, n := range []int{1, 10, 100, 1000, 10000, 100000, 300000} {
+				for ; len(exact) < n; next++ {
+					s := "element-" + strconv.Itoa(next)
+					exact.Add(s)
+					hll.Add(s)
+					// duplicates do not count
+					hll.Add(s)
+				}
+				requireCountNear(t, len(exact), hll.Count(), p)
+			}
+			require.NotNil(t, hll.registers)
+		})
+	}
+}
+
+func TestHyperLogLogSparse(t *testing.T) {
+	hll := NewHyperLogLog[int](DefaultHLLPrecision)
+	values := randomInts(1000)
+	hll.AddAll(values)
+	exact := len(NewFromSlice(values))
+	require.Nil(t, hll.registers)
+	// the sparse representation is nearly exact
+	require.InDelta(t, exact, hll.Count(), 2)
+
+	// converting keeps the counted elements
+	dense := hll.clone()
+	dense.toDense()
+	requireCountNear(t, hll.Count(), dense.Count(), DefaultHLLPrecision)
+	for i := 0; i < 1<<DefaultHLLPrecision; i++ {
+		hll.Add(-i)
+	}
+	require.NotNil(t, hll.registers)
+}
+
+func TestHyperLogLogSparseMatchesDense(t *testing.T) {
+	// folding sparse registers must give the registers of a dense sketch
+	sparse := NewHyperLogLog[int](8)
+	dense := NewHyperLogLog[int](8)
+	dense.toDense()
+	for i := 0; i < 20; i++ {
+		sparse.Add(i)
+		dense.Add(i)
+	}
+	require.Nil(t, sparse.registers)
+	sparse.toDense()
+	require.Equal(t, dense.registers, sparse.registers)
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b int
+	}{
+		{name: "sparse and sparse", a: 100, b: 200},
+		{name: "sparse and dense", a: 100, b: 50000},
+		{name: "dense and sparse", a: 50000, b: 100},
+		{name: "dense and dense", a: 50000, b: 80000},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a, b := NewHyperLogLog[int](12), NewHyperLogLog[int](12)
+			union := New[int]()
+			for i := 0; i < c.a; i++ {
+				a.Add(i)
+				union.Add(i)
+			}
+			// b overlaps a by half of the smaller sketch
+			start := min(c.a, c.b) / 2
+			for i := start; i < start+c.b; i++ {
+				b.Add(i)
+				union.Add(i)
+			}
+			intersection, err := a.IntersectionCount(b)
+			require.NoError(t, err)
+			require.InDelta(t, c.a+c.b-len(union), intersection, 4*1.04/64*float64(len(union)))
+
+			require.NoError(t, a.Merge(b))
+			requireCountNear(t, len(union), a.Count(), 12)
+		})
+	}
+
+	_, err := NewHyperLogLog[int](10).IntersectionCount(NewHyperLogLog[int](11))
+	require.ErrorIs(t, err, ErrIncompatible)
+	require.ErrorIs(t, NewHyperLogLog[int](10).Merge(NewHyperLogLog[int](11)), ErrIncompatible)
+}
+
+func TestHyperLogLogIntersectionDisjoint(t *testing.T) {
+	a, b := NewHyperLogLog[string](DefaultHLLPrecision), NewHyperLogLog[string](DefaultHLLPrecision)
+	for i := 0; i < 100; i++ {
+		a.Add("a" + strconv.Itoa(i))
+		b.Add("b" + strconv.Itoa(i))
+	}
+	intersection, err := a.IntersectionCount(b)
+	require.NoError(t, err)
+	require.LessOrEqual(t, intersection, 1)
+	require.Equal(t, 100, a.Count())
+}
@@ -0,0 +1,96 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import "iter"
+
+// ReadableSet is the read-only view shared by all set implementations of
+// this package, so functions can accept any of them. Implementations must
+// yield every element exactly once from All.
+type ReadableSet[T any] interface {
+	Len() int
+	Contains(s T) bool
+	All() iter.Seq[T]
+}
+
+// MutableSet is a ReadableSet that can be changed in place.
+type MutableSet[T any] interface {
+	ReadableSet[T]
+	Add(s T)
+	Remove(s T)
+}
+
+// Union returns the union of any two ReadableSets as new Set.
+func Union[T comparable](a, b ReadableSet[T]) Set[T] {
+	result := make(Set[T], max(a.Len(), b.Len()))
+	result.InsertSeq(a.All())
+	result.InsertSeq(b.All())
+	return result
+}
+
+// Intersection returns the intersection of any two ReadableSets as new Set.
+// It iterates the smaller set and probes the larger one.
+func Intersection[T comparable](a, b ReadableSet[T]) Set[T] {
+	if a.Len() > b.Len() {
+		a, b = b, a
+	}
+	result := New[T]()
+	for s := range a.All() {
+		if b.Contains(s) {
+			result[s] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Difference returns the elements of a not in b as new Set.
+func Difference[T comparable](a, b ReadableSet[T]) Set[T] {
+	result := New[T]()
+	for s := range a.All() {
+		if !b.Contains(s) {
+			result[s] = struct{}{}
+		}
+	}
+	return result
+}
+
+// IsSubsetOf returns true if a is a subset of b (they can be equal).
+func IsSubsetOf[T any](a, b ReadableSet[T]) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	for s := range a.All() {
+		if !b.Contains(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equals returns true if two ReadableSets hold the same elements.
+func Equals[T any](a, b ReadableSet[T]) bool {
+	return a.Len() == b.Len() && IsSubsetOf(a, b)
+}
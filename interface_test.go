@@ -0,0 +1,63 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ MutableSet[int]     = Set[int]{}
+	_ MutableSet[int]     = (*ConcurrentSet[int])(nil)
+	_ MutableSet[int]     = (*ShardedSet[int])(nil)
+	_ MutableSet[int]     = (*SortedSet[int])(nil)
+	_ MutableSet[int]     = (*OrderedSet[int])(nil)
+	_ MutableSet[uint]    = (*BitSet)(nil)
+	_ MutableSet[uint32]  = (*RoaringSet)(nil)
+	_ MutableSet[[]byte]  = (*HashSet[[]byte])(nil)
+	_ MutableSet[int]     = (*KeyedSet[string, int])(nil)
+	_ ReadableSet[int]    = PersistentSet[int]{}
+	_ ReadableSet[int]    = Frozen[int]{}
+	_ ReadableSet[string] = Set[string]{}
+)
+
+func TestReadableSetMixedBackends(t *testing.T) {
+	plain := NewFromSlice([]int{1, 2, 3, 4})
+	sorted := NewSortedFromSlice([]int{3, 4, 5})
+	persistent := NewPersistentFromSlice([]int{4, 5, 6, 7, 8, 9})
+
+	union := Union[int](plain, sorted).ToSlice()
+	slices.Sort(union)
+	require.Equal(t, []int{1, 2, 3, 4, 5}, union)
+	require.Equal(t, NewFromSlice([]int{4, 5}), Intersection[int](sorted, persistent))
+	require.Equal(t, NewFromSlice([]int{1, 2, 3}), Difference[int](plain, persistent))
+	require.True(t, Equals[int](plain.FreezeView(), plain))
+	require.True(t, IsSubsetOf[int](NewSortedFromSlice([]int{5, 6}), persistent))
+	require.False(t, IsSubsetOf[int](persistent, sorted))
+}
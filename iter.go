@@ -0,0 +1,148 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+)
+
+// All returns an iterator over the elements of a Set, in no particular order.
+func (set Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s := range set {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Sorted returns an iterator over the elements of a Set in ascending order.
+func Sorted[T cmp.Ordered](set Set[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		slice := set.ToSlice()
+		slices.Sort(slice)
+		for _, s := range slice {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Collect creates a new Set from the elements of an iterator.
+func Collect[T comparable](seq iter.Seq[T]) Set[T] {
+	set := New[T]()
+	set.InsertSeq(seq)
+	return set
+}
+
+// InsertSeq adds the elements of an iterator to a Set.
+func (set Set[T]) InsertSeq(seq iter.Seq[T]) {
+	for s := range seq {
+		set[s] = struct{}{}
+	}
+}
+
+// UnionSeq returns an iterator over the union of two Sets, without
+// materializing a new Set. Elements of set are yielded first.
+func (set Set[T]) UnionSeq(other Set[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s := range set {
+			if !yield(s) {
+				return
+			}
+		}
+		for s := range other {
+			if _, ok := set[s]; ok {
+				continue
+			}
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// IntersectionSeq returns an iterator over the intersection of two Sets,
+// without materializing a new Set.
+func (set Set[T]) IntersectionSeq(other Set[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		// walk the smaller set and probe the larger one
+		small, large := set, other
+		if len(small) > len(large) {
+			small, large = large, small
+		}
+		for s := range small {
+			if _, ok := large[s]; !ok {
+				continue
+			}
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// DifferenceSeq returns an iterator over the difference of two Sets, without
+// materializing a new Set.
+func (set Set[T]) DifferenceSeq(other Set[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s := range set {
+			if _, ok := other[s]; ok {
+				continue
+			}
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the elements of a SortedSet in ascending order.
+func (set *SortedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		set.Ascend(yield)
+	}
+}
+
+// Backward returns an iterator over the elements of a SortedSet in descending
+// order.
+func (set *SortedSet[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		set.Descend(yield)
+	}
+}
+
+// All returns an iterator over the elements of an OrderedSet in insertion
+// order.
+func (set *OrderedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		set.Each(yield)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// firstN collects at most n elements of seq, breaking out of the loop early.
+func firstN[T any](seq iter.Seq[T], n int) []T {
+	result := make([]T, 0, n)
+	for s := range seq {
+		if len(result) == n {
+			break
+		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func TestSetAll(t *testing.T) {
+	set := NewFromSlice([]string{"a", "b", "c"})
+	actual := slices.Collect(set.All())
+	slices.Sort(actual)
+	require.Equal(t, []string{"a", "b", "c"}, actual)
+
+	require.Len(t, firstN(set.All(), 2), 2)
+	require.Empty(t, slices.Collect(New[string]().All()))
+}
+
+func TestSorted(t *testing.T) {
+	set := NewFromSlice([]int{3, 1, 2})
+	require.Equal(t, []int{1, 2, 3}, slices.Collect(Sorted(set)))
+	require.Equal(t, []int{1, 2}, firstN(Sorted(set), 2))
+}
+
+func TestCollect(t *testing.T) {
+	cases := []struct {
+		name     string
+		seq      iter.Seq[string]
+		expected Set[string]
+	}{
+		{
+			name:     "empty sequence",
+			seq:      slices.Values([]string{}),
+			expected: Set[string]{},
+		},
+		{
+			name:     "slice values",
+			seq:      slices.Values([]string{"a", "b", "a"}),
+			expected: Set[string]{"a": struct{}{}, "b": struct{}{}},
+		},
+		{
+			name:     "map keys",
+			seq:      maps.Keys(map[string]int{"a": 1, "c": 3}),
+			expected: Set[string]{"a": struct{}{}, "c": struct{}{}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := Collect(c.seq)
+			require.Equal(t, c.expected, actual, "expected %v, got %v", c.expected, actual)
+		})
+	}
+}
+
+func TestSetInsertSeq(t *testing.T) {
+	set := NewFromSlice([]int{1, 2})
+	set.InsertSeq(slices.Values([]int{2, 3}))
+	require.Equal(t, Set[int]{1: struct{}{}, 2: struct{}{}, 3: struct{}{}}, set)
+}
+
+func TestSetLazyOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          Set[string]
+		other        Set[string]
+		union        []string
+		intersection []string
+		difference   []string
+	}{
+		{
+			name:         "empty sets",
+			set:          Set[string]{},
+			other:        Set[string]{},
+			union:        []string{},
+			intersection: []string{},
+			difference:   []string{},
+		},
+		{
+			name:         "partial overlap",
+			set:          NewFromSlice([]string{"a", "b", "c"}),
+			other:        NewFromSlice([]string{"b", "c", "d"}),
+			union:        []string{"a", "b", "c", "d"},
+			intersection: []string{"b", "c"},
+			difference:   []string{"a"},
+		},
+		{
+			name:         "larger receiver",
+			set:          NewFromSlice([]string{"a", "b", "c", "d"}),
+			other:        NewFromSlice([]string{"d"}),
+			union:        []string{"a", "b", "c", "d"},
+			intersection: []string{"d"},
+			difference:   []string{"a", "b", "c"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.ElementsMatch(t, c.union, slices.Collect(c.set.UnionSeq(c.other)))
+			require.ElementsMatch(t, c.intersection, slices.Collect(c.set.IntersectionSeq(c.other)))
+			require.ElementsMatch(t, c.difference, slices.Collect(c.set.DifferenceSeq(c.other)))
+			require.Equal(t, c.set.Union(c.other), Collect(c.set.UnionSeq(c.other)))
+		})
+	}
+}
+
+func TestSetLazyOperationsEarlyBreak(t *testing.T) {
+	set := NewFromSlice([]int{1, 2, 3, 4})
+	other := NewFromSlice([]int{3, 4, 5, 6})
+	for _, n := range []int{0, 1, 2, 5} {
+		require.Len(t, firstN(set.UnionSeq(other), n), n)
+	}
+	require.Len(t, firstN(set.IntersectionSeq(other), 1), 1)
+	require.Len(t, firstN(set.DifferenceSeq(other), 1), 1)
+}
+
+func TestSortedSetAll(t *testing.T) {
+	set := NewSortedFromSlice([]int{3, 1, 2})
+	require.Equal(t, []int{1, 2, 3}, slices.Collect(set.All()))
+	require.Equal(t, []int{3, 2, 1}, slices.Collect(set.Backward()))
+	require.Equal(t, []int{1, 2}, firstN(set.All(), 2))
+	require.Equal(t, []int{3}, firstN(set.Backward(), 1))
+}
+
+func TestOrderedSetAll(t *testing.T) {
+	set := NewOrderedFromSlice([]string{"c", "a", "b"})
+	require.Equal(t, []string{"c", "a", "b"}, slices.Collect(set.All()))
+	require.Equal(t, []string{"c"}, firstN(set.All(), 1))
+}
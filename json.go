@@ -0,0 +1,144 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ErrDuplicateElement is returned when decoding a StrictSet from input
+// holding the same element more than once.
+var ErrDuplicateElement = errors.New("set: duplicate element")
+
+// MarshalJSON implements json.Marshaler. A Set is encoded as a JSON array.
+// Elements whose underlying type is a string, integer or float are sorted by
+// value, other elements are sorted by their JSON encoding, so the output is
+// always deterministic. A nil Set is encoded as null.
+func (set Set[T]) MarshalJSON() ([]byte, error) {
+	if set == nil {
+		return []byte("null"), nil
+	}
+	slice := set.ToSlice()
+	ordered := sortOrdered(slice)
+
+	items := make([][]byte, len(slice))
+	for i, s := range slice {
+		b, err := json.Marshal(s)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = b
+	}
+	if !ordered {
+		slices.SortFunc(items, bytes.Compare)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, b := range items {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It replaces the content of a Set
+// with the elements of a JSON array, duplicates are silently merged. A JSON
+// null leaves the Set unchanged.
+func (set *Set[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON(set, data, false)
+}
+
+// StrictSet is a Set whose UnmarshalJSON rejects input holding duplicate
+// elements with ErrDuplicateElement. Convert between the two types with
+// Set[T](strict) and StrictSet[T](set).
+type StrictSet[T comparable] Set[T]
+
+// MarshalJSON implements json.Marshaler, see Set.MarshalJSON.
+func (set StrictSet[T]) MarshalJSON() ([]byte, error) {
+	return Set[T](set).MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler, see Set.UnmarshalJSON. It fails
+// with ErrDuplicateElement if an element appears more than once.
+func (set *StrictSet[T]) UnmarshalJSON(data []byte) error {
+	return unmarshalJSON((*Set[T])(set), data, true)
+}
+
+func unmarshalJSON[T comparable](set *Set[T], data []byte, strict bool) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	var slice []T
+	if err := json.Unmarshal(data, &slice); err != nil {
+		return err
+	}
+	result := make(Set[T], len(slice))
+	for _, s := range slice {
+		if _, ok := result[s]; ok && strict {
+			return fmt.Errorf("%w: %v", ErrDuplicateElement, s)
+		}
+		result[s] = struct{}{}
+	}
+	*set = result
+	return nil
+}
+
+// sortOrdered sorts a slice in place if the underlying type of its elements
+// is a string, integer or float, and reports whether it did.
+func sortOrdered[T any](slice []T) bool {
+	var zero T
+	switch reflect.ValueOf(&zero).Elem().Kind() {
+	case reflect.String:
+		slices.SortFunc(slice, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		slices.SortFunc(slice, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		slices.SortFunc(slice, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		})
+	case reflect.Float32, reflect.Float64:
+		slices.SortFunc(slice, func(a, b T) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		})
+	default:
+		return false
+	}
+	return true
+}
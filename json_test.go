@@ -0,0 +1,167 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type jsonPoint struct {
+	X int    `json:"x"`
+	Y int    `json:"y"`
+	L string `json:"label"`
+}
+
+func TestSetMarshalJSON(t *testing.T) {
+	type myString string
+	cases := []struct {
+		name     string
+		set      any
+		expected string
+	}{
+		{name: "nil set", set: Set[int](nil), expected: `null`},
+		{name: "empty set", set: Set[int]{}, expected: `[]`},
+		{name: "ints", set: NewFromSlice([]int{10, -2, 3, 0}), expected: `[-2,0,3,10]`},
+		{name: "uints", set: NewFromSlice([]uint8{200, 3, 7}), expected: `[3,7,200]`},
+		{name: "floats", set: NewFromSlice([]float64{2.5, -1, 0.25}), expected: `[-1,0.25,2.5]`},
+		{name: "strings", set: NewFromSlice([]string{"b", "c", "a"}), expected: `["a","b","c"]`},
+		{name: "named strings", set: NewFromSlice([]myString{"y", "x"}), expected: `["x","y"]`},
+		{
+			name:     "structs",
+			set:      NewFromSlice([]jsonPoint{{2, 1, "b"}, {1, 2, "a"}}),
+			expected: `[{"x":1,"y":2,"label":"a"},{"x":2,"y":1,"label":"b"}]`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := json.Marshal(c.set)
+			require.NoError(t, err)
+			require.Equal(t, c.expected, string(actual))
+		})
+	}
+}
+
+func TestSetMarshalJSONError(t *testing.T) {
+	_, err := json.Marshal(NewFromSlice([]any{make(chan int)}))
+	require.Error(t, err)
+}
+
+func TestSetJSONRoundTrip(t *testing.T) {
+	t.Run("ints", func(t *testing.T) {
+		testJSONRoundTrip(t, NewFromSlice([]int{1, 2, 3, -42}))
+	})
+	t.Run("strings", func(t *testing.T) {
+		testJSONRoundTrip(t, NewFromSlice([]string{"a", "b", "", "\"quoted\""}))
+	})
+	t.Run("structs", func(t *testing.T) {
+		testJSONRoundTrip(t, NewFromSlice([]jsonPoint{{1, 2, "a"}, {3, 4, "b"}}))
+	})
+	t.Run("empty", func(t *testing.T) {
+		testJSONRoundTrip(t, New[int]())
+	})
+}
+
+func testJSONRoundTrip[T comparable](t *testing.T, set Set[T]) {
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+	var actual Set[T]
+	require.NoError(t, json.Unmarshal(data, &actual))
+	require.Equal(t, set, actual)
+}
+
+func TestSetUnmarshalJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		data     string
+		expected Set[string]
+		err      bool
+	}{
+		{name: "empty array", data: `[]`, expected: Set[string]{}},
+		{name: "elements", data: `["a","b"]`, expected: Set[string]{"a": struct{}{}, "b": struct{}{}}},
+		{name: "duplicates", data: `["a","a"]`, expected: Set[string]{"a": struct{}{}}},
+		{name: "object", data: `{"a":{}}`, err: true},
+		{name: "wrong element type", data: `[1]`, err: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var actual Set[string]
+			err := json.Unmarshal([]byte(c.data), &actual)
+			if c.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestSetUnmarshalJSONReplaces(t *testing.T) {
+	set := NewFromSlice([]int{1, 2})
+	require.NoError(t, json.Unmarshal([]byte(`[3]`), &set))
+	require.Equal(t, Set[int]{3: struct{}{}}, set)
+
+	require.NoError(t, json.Unmarshal([]byte(`null`), &set))
+	require.Equal(t, Set[int]{3: struct{}{}}, set)
+}
+
+func TestStrictSetJSON(t *testing.T) {
+	var strict StrictSet[int]
+	require.NoError(t, json.Unmarshal([]byte(`[1,2]`), &strict))
+	require.Equal(t, NewFromSlice([]int{1, 2}), Set[int](strict))
+
+	err := json.Unmarshal([]byte(`[1,2,1]`), &strict)
+	require.ErrorIs(t, err, ErrDuplicateElement)
+
+	data, err := json.Marshal(strict)
+	require.NoError(t, err)
+	require.Equal(t, `[1,2]`, string(data))
+}
+
+func TestSetJSONStructField(t *testing.T) {
+	type payload struct {
+		Tags  Set[string]    `json:"tags"`
+		IDs   StrictSet[int] `json:"ids"`
+		Empty Set[string]    `json:"empty,omitempty"`
+	}
+	in := payload{
+		Tags: NewFromSlice([]string{"z", "a"}),
+		IDs:  StrictSet[int](NewFromSlice([]int{2, 1})),
+	}
+	data, err := json.Marshal(in)
+	require.NoError(t, err)
+	require.Equal(t, `{"tags":["a","z"],"ids":[1,2]}`, string(data))
+
+	var out payload
+	require.NoError(t, json.Unmarshal(data, &out))
+	require.Equal(t, in, out)
+
+	err = json.Unmarshal([]byte(`{"ids":[1,1]}`), &out)
+	require.ErrorIs(t, err, ErrDuplicateElement)
+}
@@ -0,0 +1,238 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import "iter"
+
+// KeyedSet is a generic, not threadsafe set of values deduplicated by a key
+// extracted from each value. When a value is added under a key already
+// present, a merge function decides which value is kept.
+type KeyedSet[K comparable, V any] struct {
+	items map[K]V
+	key   func(V) K
+	merge func(existing, added V) V
+}
+
+// KeepFirst is a merge function keeping the value already in a KeyedSet.
+func KeepFirst[V any](existing, _ V) V {
+	return existing
+}
+
+// KeepLast is a merge function replacing the value in a KeyedSet with the
+// added one.
+func KeepLast[V any](_, added V) V {
+	return added
+}
+
+// NewKeyed creates a new KeyedSet deduplicating values by key. Adding a value
+// under a key already present keeps the existing value.
+func NewKeyed[K comparable, V any](key func(V) K) *KeyedSet[K, V] {
+	return NewKeyedWithMerge(key, KeepFirst[V])
+}
+
+// NewKeyedFromSlice creates a new KeyedSet from a slice, keeping the first
+// value of each key.
+func NewKeyedFromSlice[K comparable, V any](key func(V) K, slice []V) *KeyedSet[K, V] {
+	ks := NewKeyed(key)
+	ks.AddAll(slice)
+	return ks
+}
+
+// NewKeyedWithMerge creates a new KeyedSet resolving values added under a key
+// already present with merge, which receives the existing and the added
+// value and returns the one to store. KeepFirst and KeepLast are ready-made
+// merge functions. Merge must return a value with the same key as its
+// arguments; Add, Union and Intersection panic otherwise, as the value could
+// not be found under its key anymore.
+func NewKeyedWithMerge[K comparable, V any](key func(V) K, merge func(existing, added V) V) *KeyedSet[K, V] {
+	return &KeyedSet[K, V]{items: make(map[K]V), key: key, merge: merge}
+}
+
+// Len returns the number of values in a KeyedSet.
+func (ks *KeyedSet[K, V]) Len() int {
+	return len(ks.items)
+}
+
+// Get returns the value stored under a key, or false if there is none.
+func (ks *KeyedSet[K, V]) Get(k K) (V, bool) {
+	v, ok := ks.items[k]
+	return v, ok
+}
+
+// Keys returns the keys of a KeyedSet as a Set.
+func (ks *KeyedSet[K, V]) Keys() Set[K] {
+	return NewFromMapKeys(ks.items)
+}
+
+// ToSlice returns an unordered slice of values from a KeyedSet.
+func (ks *KeyedSet[K, V]) ToSlice() []V {
+	slice := make([]V, 0, len(ks.items))
+	for _, v := range ks.items {
+		slice = append(slice, v)
+	}
+	return slice
+}
+
+// All returns an iterator over the values of a KeyedSet, in no particular
+// order.
+func (ks *KeyedSet[K, V]) All() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range ks.items {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if a KeyedSet holds a value with the same key as v.
+func (ks *KeyedSet[K, V]) Contains(v V) bool {
+	_, ok := ks.items[ks.key(v)]
+	return ok
+}
+
+// ContainsKey returns true if a KeyedSet holds a value under a key.
+func (ks *KeyedSet[K, V]) ContainsKey(k K) bool {
+	_, ok := ks.items[k]
+	return ok
+}
+
+// Add adds a value to a KeyedSet, merging it with the value already stored
+// under its key, if any.
+func (ks *KeyedSet[K, V]) Add(v V) {
+	k := ks.key(v)
+	if existing, ok := ks.items[k]; ok {
+		v = ks.mergeKeyed(k, existing, v)
+	}
+	ks.items[k] = v
+}
+
+// Remove removes the value with the same key as v from a KeyedSet.
+func (ks *KeyedSet[K, V]) Remove(v V) {
+	delete(ks.items, ks.key(v))
+}
+
+// RemoveKey removes the value stored under a key from a KeyedSet.
+func (ks *KeyedSet[K, V]) RemoveKey(k K) {
+	delete(ks.items, k)
+}
+
+// AddAll adds a slice of values to a KeyedSet.
+func (ks *KeyedSet[K, V]) AddAll(slice []V) {
+	for _, v := range slice {
+		ks.Add(v)
+	}
+}
+
+// RemoveAll removes the values with the same keys as a slice of values from
+// a KeyedSet.
+func (ks *KeyedSet[K, V]) RemoveAll(slice []V) {
+	for _, v := range slice {
+		ks.Remove(v)
+	}
+}
+
+// Equals returns true if two KeyedSets hold the same keys, regardless of
+// their values.
+func (ks *KeyedSet[K, V]) Equals(other *KeyedSet[K, V]) bool {
+	return len(ks.items) == len(other.items) && ks.IsSubsetOf(other)
+}
+
+// IsSubsetOf returns true if the keys of a KeyedSet are a subset of the keys
+// of another KeyedSet (they can be equal).
+func (ks *KeyedSet[K, V]) IsSubsetOf(other *KeyedSet[K, V]) bool {
+	if len(ks.items) > len(other.items) {
+		return false
+	}
+	for k := range ks.items {
+		if _, ok := other.items[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProperSubsetOf returns true if the keys of a KeyedSet are a proper subset
+// of the keys of another KeyedSet (they cannot be equal).
+func (ks *KeyedSet[K, V]) IsProperSubsetOf(other *KeyedSet[K, V]) bool {
+	return len(ks.items) < len(other.items) && ks.IsSubsetOf(other)
+}
+
+// Union returns the union of two KeyedSets by key as new KeyedSet. Values
+// under keys present in both are merged by the receiver, as if the values of
+// other were added to a copy of it.
+func (ks *KeyedSet[K, V]) Union(other *KeyedSet[K, V]) *KeyedSet[K, V] {
+	result := ks.empty()
+	for k, v := range ks.items {
+		result.items[k] = v
+	}
+	for k, v := range other.items {
+		if existing, ok := result.items[k]; ok {
+			v = ks.mergeKeyed(k, existing, v)
+		}
+		result.items[k] = v
+	}
+	return result
+}
+
+// Intersection returns the intersection of two KeyedSets by key as new
+// KeyedSet. The values of both are merged by the receiver, as in Union.
+func (ks *KeyedSet[K, V]) Intersection(other *KeyedSet[K, V]) *KeyedSet[K, V] {
+	result := ks.empty()
+	for k, v := range ks.items {
+		if w, ok := other.items[k]; ok {
+			result.items[k] = ks.mergeKeyed(k, v, w)
+		}
+	}
+	return result
+}
+
+// Difference returns the values of a KeyedSet whose keys are not in another
+// KeyedSet as new KeyedSet.
+func (ks *KeyedSet[K, V]) Difference(other *KeyedSet[K, V]) *KeyedSet[K, V] {
+	result := ks.empty()
+	for k, v := range ks.items {
+		if _, ok := other.items[k]; !ok {
+			result.items[k] = v
+		}
+	}
+	return result
+}
+
+// mergeKeyed merges two values stored under key k, checking that the result
+// keeps the key.
+func (ks *KeyedSet[K, V]) mergeKeyed(k K, existing, added V) V {
+	v := ks.merge(existing, added)
+	if ks.key(v) != k {
+		panic("set: KeyedSet merge function changed the key")
+	}
+	return v
+}
+
+// empty returns a new, empty KeyedSet with the same key and merge functions.
+func (ks *KeyedSet[K, V]) empty() *KeyedSet[K, V] {
+	return NewKeyedWithMerge(ks.key, ks.merge)
+}
@@ -0,0 +1,144 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID     int
+	Name   string
+	Visits int
+}
+
+func userID(u user) int { return u.ID }
+
+func sortedUsers(slice []user) []user {
+	slices.SortFunc(slice, func(a, b user) int { return a.ID - b.ID })
+	return slice
+}
+
+func TestKeyedSetBasic(t *testing.T) {
+	ks := NewKeyedFromSlice(userID, []user{{1, "ann", 1}, {2, "bob", 1}, {1, "anne", 5}})
+	require.Equal(t, 2, ks.Len())
+	require.Equal(t, NewFromSlice([]int{1, 2}), ks.Keys())
+
+	u, ok := ks.Get(1)
+	require.True(t, ok)
+	require.Equal(t, user{1, "ann", 1}, u)
+	_, ok = ks.Get(3)
+	require.False(t, ok)
+
+	require.True(t, ks.Contains(user{ID: 2}))
+	require.False(t, ks.Contains(user{ID: 3, Name: "bob"}))
+	require.True(t, ks.ContainsKey(1))
+	require.False(t, ks.ContainsKey(3))
+
+	ks.AddAll([]user{{3, "cy", 1}, {4, "dee", 1}})
+	ks.Remove(user{ID: 2})
+	ks.RemoveKey(4)
+	ks.RemoveAll([]user{{ID: 9}})
+	require.Equal(t, []user{{1, "ann", 1}, {3, "cy", 1}}, sortedUsers(ks.ToSlice()))
+	require.Equal(t, sortedUsers(ks.ToSlice()), sortedUsers(slices.Collect(ks.All())))
+	require.Len(t, firstN(ks.All(), 1), 1)
+}
+
+func TestKeyedSetMerge(t *testing.T) {
+	values := []user{{1, "ann", 1}, {1, "anne", 2}, {2, "bob", 3}}
+	cases := []struct {
+		name     string
+		merge    func(existing, added user) user
+		expected []user
+	}{
+		{name: "keep first", merge: KeepFirst[user], expected: []user{{1, "ann", 1}, {2, "bob", 3}}},
+		{name: "keep last", merge: KeepLast[user], expected: []user{{1, "anne", 2}, {2, "bob", 3}}},
+		{
+			name: "merge func",
+			merge: func(existing, added user) user {
+				existing.Visits += added.Visits
+				return existing
+			},
+			expected: []user{{1, "ann", 3}, {2, "bob", 3}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ks := NewKeyedWithMerge(userID, c.merge)
+			ks.AddAll(values)
+			require.Equal(t, c.expected, sortedUsers(ks.ToSlice()))
+		})
+	}
+}
+
+func TestKeyedSetMergeChangingKey(t *testing.T) {
+	renumber := func(existing, added user) user {
+		return user{existing.ID + 100, added.Name, existing.Visits + added.Visits}
+	}
+	ks := NewKeyedWithMerge(userID, renumber)
+	ks.Add(user{1, "ann", 1})
+	require.Panics(t, func() { ks.Add(user{1, "annie", 1}) })
+	// the stored value is still found under its key
+	require.True(t, ks.Contains(user{1, "", 0}))
+	require.Equal(t, []user{{1, "ann", 1}}, ks.ToSlice())
+
+	other := NewKeyedFromSlice(userID, []user{{1, "bob", 1}})
+	require.Panics(t, func() { ks.Union(other) })
+	require.Panics(t, func() { ks.Intersection(other) })
+	ks.Remove(user{1, "", 0})
+	require.Equal(t, 0, ks.Len())
+}
+
+func TestKeyedSetOperations(t *testing.T) {
+	set := NewKeyedWithMerge(userID, KeepLast[user])
+	set.AddAll([]user{{1, "ann", 1}, {2, "bob", 1}, {3, "cy", 1}})
+	other := NewKeyedFromSlice(userID, []user{{2, "bobby", 2}, {3, "cyrus", 2}, {4, "dee", 2}})
+
+	union := set.Union(other)
+	require.Equal(t, []user{{1, "ann", 1}, {2, "bobby", 2}, {3, "cyrus", 2}, {4, "dee", 2}}, sortedUsers(union.ToSlice()))
+	require.Equal(t, []user{{2, "bobby", 2}, {3, "cyrus", 2}}, sortedUsers(set.Intersection(other).ToSlice()))
+	require.Equal(t, []user{{1, "ann", 1}}, sortedUsers(set.Difference(other).ToSlice()))
+	require.Equal(t, []user{{2, "bobby", 2}, {3, "cyrus", 2}}, sortedUsers(other.Intersection(set).ToSlice()))
+	require.Equal(t, []user{{4, "dee", 2}}, sortedUsers(other.Difference(set).ToSlice()))
+
+	// results keep the merge function of the receiver
+	union.Add(user{1, "annie", 9})
+	u, _ := union.Get(1)
+	require.Equal(t, "annie", u.Name)
+
+	require.False(t, set.Equals(other))
+	require.False(t, set.IsSubsetOf(other))
+	require.True(t, set.IsSubsetOf(union))
+	require.True(t, set.IsProperSubsetOf(union))
+	require.False(t, union.IsProperSubsetOf(union))
+
+	renamed := NewKeyedFromSlice(userID, []user{{3, "x", 0}, {2, "y", 0}, {1, "z", 0}})
+	require.True(t, set.Equals(renamed))
+	require.True(t, NewKeyed(userID).Equals(NewKeyed(userID)))
+}
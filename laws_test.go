@@ -0,0 +1,172 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+// intInput generates Set[int] values drawn from a small domain, so that
+// random sets overlap, nest and coincide often.
+type intInput Set[int]
+
+func (intInput) Generate(rnd *rand.Rand, size int) reflect.Value {
+	set := New[int]()
+	for i := rnd.Intn(size + 1); i > 0; i-- {
+		set.Add(rnd.Intn(32) - 8)
+	}
+	return reflect.ValueOf(intInput(set))
+}
+
+// stringInput generates Set[string] values of short strings over a tiny
+// alphabet, including the empty string.
+type stringInput Set[string]
+
+func (stringInput) Generate(rnd *rand.Rand, size int) reflect.Value {
+	set := New[string]()
+	for i := rnd.Intn(size + 1); i > 0; i-- {
+		b := make([]byte, rnd.Intn(3))
+		for j := range b {
+			b[j] = "abc"[rnd.Intn(3)]
+		}
+		set.Add(string(b))
+	}
+	return reflect.ValueOf(stringInput(set))
+}
+
+// checkLaws returns an error naming the first law of set algebra that does
+// not hold for a, b and c. The operands must not be modified.
+func checkLaws[T comparable](a, b, c Set[T]) error {
+	before := []Set[T]{a.clone(), b.clone(), c.clone()}
+	laws := []struct {
+		name string
+		ok   bool
+	}{
+		{"A∪B == B∪A", a.Union(b).Equals(b.Union(a))},
+		{"A∩B == B∩A", a.Intersection(b).Equals(b.Intersection(a))},
+		{"(A∪B)∪C == A∪(B∪C)", a.Union(b).Union(c).Equals(a.Union(b.Union(c)))},
+		{"(A∩B)∩C == A∩(B∩C)", a.Intersection(b).Intersection(c).Equals(a.Intersection(b.Intersection(c)))},
+		{"A∩(B∪C) == (A∩B)∪(A∩C)", a.Intersection(b.Union(c)).Equals(a.Intersection(b).Union(a.Intersection(c)))},
+		{"A∖(B∪C) == (A∖B)∩(A∖C)", a.Difference(b.Union(c)).Equals(a.Difference(b).Intersection(a.Difference(c)))},
+		{"A∖(B∩C) == (A∖B)∪(A∖C)", a.Difference(b.Intersection(c)).Equals(a.Difference(b).Union(a.Difference(c)))},
+		{"A∪A == A", a.Union(a).Equals(a)},
+		{"A∖A == ∅", len(a.Difference(a)) == 0},
+		{"A∩B ⊆ A ⊆ A∪B", a.Intersection(b).IsSubsetOf(a) && a.IsSubsetOf(a.Union(b))},
+		{"|A∪B| == |A|+|B|-|A∩B|", len(a.Union(b)) == len(a)+len(b)-len(a.Intersection(b))},
+		{"A ⊊ B == A ⊆ B && A != B", a.IsProperSubsetOf(b) == (a.IsSubsetOf(b) && !a.Equals(b))},
+		{"A == B == A ⊆ B && B ⊆ A", a.Equals(b) == (a.IsSubsetOf(b) && b.IsSubsetOf(a))},
+		{"A ⊊ B implies not B ⊆ A", !a.IsProperSubsetOf(b) || !b.IsSubsetOf(a)},
+		{"NewFromSlice(A.ToSlice()) == A", NewFromSlice(a.ToSlice()).Equals(a) && len(a.ToSlice()) == len(a)},
+	}
+	for _, law := range laws {
+		if !law.ok {
+			return fmt.Errorf("%s does not hold for A=%v B=%v C=%v", law.name, a.ToSlice(), b.ToSlice(), c.ToSlice())
+		}
+	}
+	for i, s := range []Set[T]{a, b, c} {
+		if !s.Equals(before[i]) {
+			return fmt.Errorf("operand %d was modified", i)
+		}
+	}
+	return nil
+}
+
+func TestSetLawsQuick(t *testing.T) {
+	t.Run("int", func(t *testing.T) {
+		config := &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(1))}
+		property := func(a, b, c intInput) bool {
+			err := checkLaws(Set[int](a), Set[int](b), Set[int](c))
+			if err != nil {
+				t.Log(err)
+			}
+			return err == nil
+		}
+		require.NoError(t, quick.Check(property, config))
+	})
+	t.Run("string", func(t *testing.T) {
+		config := &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(2))}
+		property := func(a, b, c stringInput) bool {
+			err := checkLaws(Set[string](a), Set[string](b), Set[string](c))
+			if err != nil {
+				t.Log(err)
+			}
+			return err == nil
+		}
+		require.NoError(t, quick.Check(property, config))
+	})
+}
+
+func TestSetAddRemoveQuick(t *testing.T) {
+	config := &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(3))}
+	property := func(in intInput, s int) bool {
+		set := Set[int](in).clone()
+		set.Add(s)
+		added := set.Contains(s) && Set[int](in).IsSubsetOf(set)
+		set.Remove(s)
+		removed := !set.Contains(s) && set.IsSubsetOf(Set[int](in))
+		return added && removed
+	}
+	require.NoError(t, quick.Check(property, config))
+}
+
+func FuzzSetLawsInt(f *testing.F) {
+	f.Add([]byte{}, []byte{}, []byte{})
+	f.Add([]byte{1, 2, 3}, []byte{2, 3, 4}, []byte{3})
+	f.Add([]byte{1, 2}, []byte{1, 2, 3}, []byte{1, 2})
+	f.Fuzz(func(t *testing.T, a, b, c []byte) {
+		toSet := func(data []byte) Set[int] {
+			set := New[int]()
+			for _, d := range data {
+				set.Add(int(int8(d)))
+			}
+			return set
+		}
+		if err := checkLaws(toSet(a), toSet(b), toSet(c)); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func FuzzSetLawsString(f *testing.F) {
+	f.Add("", "", "")
+	f.Add("a b c", "b c d", "c")
+	f.Add("a,b", "a b", ",")
+	f.Fuzz(func(t *testing.T, a, b, c string) {
+		toSet := func(data string) Set[string] {
+			return NewFromSlice(strings.Split(data, " "))
+		}
+		if err := checkLaws(toSet(a), toSet(b), toSet(c)); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
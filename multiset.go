@@ -0,0 +1,265 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import "slices"
+
+// MultiSet is a generic, not threadsafe bag data structure counting the
+// occurrences of each element. Elements are only present with a positive
+// count; use the methods rather than writing to the map directly to keep it
+// that way.
+type MultiSet[T comparable] map[T]int
+
+// Counted is an element of a MultiSet together with its count.
+type Counted[T comparable] struct {
+	Item  T
+	Count int
+}
+
+// NewMultiSet creates a new MultiSet.
+func NewMultiSet[T comparable]() MultiSet[T] {
+	return make(MultiSet[T])
+}
+
+// NewMultiSetFromSlice creates a new MultiSet from a slice of comparable,
+// counting every occurrence.
+func NewMultiSetFromSlice[T comparable](slice []T) MultiSet[T] {
+	ms := make(MultiSet[T])
+	for _, s := range slice {
+		ms[s]++
+	}
+	return ms
+}
+
+// NewMultiSetFromSet creates a new MultiSet holding every element of a Set
+// once.
+func NewMultiSetFromSet[T comparable](set Set[T]) MultiSet[T] {
+	ms := make(MultiSet[T], len(set))
+	for s := range set {
+		ms[s] = 1
+	}
+	return ms
+}
+
+// NewMultiSetFromCounts creates a new MultiSet from a map of counts. Elements
+// with a count lower than one are left out.
+func NewMultiSetFromCounts[T comparable](counts map[T]int) MultiSet[T] {
+	ms := make(MultiSet[T], len(counts))
+	for s, n := range counts {
+		if n > 0 {
+			ms[s] = n
+		}
+	}
+	return ms
+}
+
+// Count returns the number of occurrences of an element in a MultiSet.
+func (ms MultiSet[T]) Count(s T) int {
+	return ms[s]
+}
+
+// Contains returns true if a MultiSet contains at least one occurrence of an
+// element.
+func (ms MultiSet[T]) Contains(s T) bool {
+	return ms[s] > 0
+}
+
+// Distinct returns the distinct elements of a MultiSet as a Set.
+func (ms MultiSet[T]) Distinct() Set[T] {
+	set := make(Set[T], len(ms))
+	for s := range ms {
+		set[s] = struct{}{}
+	}
+	return set
+}
+
+// TotalLen returns the number of occurrences of all elements in a MultiSet.
+func (ms MultiSet[T]) TotalLen() int {
+	total := 0
+	for _, n := range ms {
+		total += n
+	}
+	return total
+}
+
+// ToSlice returns an unordered slice of elements from a MultiSet, each
+// repeated as many times as it occurs.
+func (ms MultiSet[T]) ToSlice() []T {
+	slice := make([]T, 0, ms.TotalLen())
+	for s, n := range ms {
+		for ; n > 0; n-- {
+			slice = append(slice, s)
+		}
+	}
+	return slice
+}
+
+// MostCommon returns the k most frequent elements of a MultiSet with their
+// counts, most frequent first. Elements with equal counts are ordered by
+// value if they are strings, integers or floats. All elements are returned
+// if k is negative or larger than the number of distinct elements.
+func (ms MultiSet[T]) MostCommon(k int) []Counted[T] {
+	items := make([]T, 0, len(ms))
+	for s := range ms {
+		items = append(items, s)
+	}
+	sortOrdered(items)
+	counted := make([]Counted[T], len(items))
+	for i, s := range items {
+		counted[i] = Counted[T]{Item: s, Count: ms[s]}
+	}
+	slices.SortStableFunc(counted, func(a, b Counted[T]) int {
+		return b.Count - a.Count
+	})
+	if k >= 0 && k < len(counted) {
+		counted = counted[:k]
+	}
+	return counted
+}
+
+// Add adds one occurrence of an element to a MultiSet.
+func (ms MultiSet[T]) Add(s T) {
+	ms[s]++
+}
+
+// AddN adds n occurrences of an element to a MultiSet. It does nothing if n
+// is not positive.
+func (ms MultiSet[T]) AddN(s T, n int) {
+	if n > 0 {
+		ms[s] += n
+	}
+}
+
+// Remove removes one occurrence of an element from a MultiSet.
+func (ms MultiSet[T]) Remove(s T) {
+	ms.RemoveN(s, 1)
+}
+
+// RemoveN removes up to n occurrences of an element from a MultiSet. It does
+// nothing if n is not positive.
+func (ms MultiSet[T]) RemoveN(s T, n int) {
+	if n <= 0 {
+		return
+	}
+	if ms[s] <= n {
+		delete(ms, s)
+	} else {
+		ms[s] -= n
+	}
+}
+
+// AddAll adds one occurrence of each element of a slice to a MultiSet.
+func (ms MultiSet[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		ms[s]++
+	}
+}
+
+// RemoveAll removes one occurrence of each element of a slice from a
+// MultiSet.
+func (ms MultiSet[T]) RemoveAll(slice []T) {
+	for _, s := range slice {
+		ms.RemoveN(s, 1)
+	}
+}
+
+// Equals returns true if two MultiSets hold the same elements with the same
+// counts.
+func (ms MultiSet[T]) Equals(other MultiSet[T]) bool {
+	if len(ms) != len(other) {
+		return false
+	}
+	for s, n := range ms {
+		if other[s] != n {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubsetOf returns true if every element of a MultiSet occurs at least as
+// many times in another MultiSet (they can be equal).
+func (ms MultiSet[T]) IsSubsetOf(other MultiSet[T]) bool {
+	if len(ms) > len(other) {
+		return false
+	}
+	for s, n := range ms {
+		if other[s] < n {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns the union of two MultiSets as new MultiSet, keeping the
+// larger count of each element.
+func (ms MultiSet[T]) Union(other MultiSet[T]) MultiSet[T] {
+	result := make(MultiSet[T], max(len(ms), len(other)))
+	for s, n := range ms {
+		result[s] = n
+	}
+	for s, n := range other {
+		result[s] = max(result[s], n)
+	}
+	return result
+}
+
+// Sum returns the sum of two MultiSets as new MultiSet, adding the counts of
+// each element.
+func (ms MultiSet[T]) Sum(other MultiSet[T]) MultiSet[T] {
+	result := make(MultiSet[T], max(len(ms), len(other)))
+	for s, n := range ms {
+		result[s] = n
+	}
+	for s, n := range other {
+		result[s] += n
+	}
+	return result
+}
+
+// Intersection returns the intersection of two MultiSets as new MultiSet,
+// keeping the smaller count of each element.
+func (ms MultiSet[T]) Intersection(other MultiSet[T]) MultiSet[T] {
+	result := make(MultiSet[T])
+	for s, n := range ms {
+		if m := min(n, other[s]); m > 0 {
+			result[s] = m
+		}
+	}
+	return result
+}
+
+// Difference returns the difference of two MultiSets as new MultiSet,
+// subtracting the counts of other and dropping elements left with none.
+func (ms MultiSet[T]) Difference(other MultiSet[T]) MultiSet[T] {
+	result := make(MultiSet[T])
+	for s, n := range ms {
+		if m := n - other[s]; m > 0 {
+			result[s] = m
+		}
+	}
+	return result
+}
@@ -0,0 +1,160 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiSetBasic(t *testing.T) {
+	ms := NewMultiSetFromSlice([]string{"a", "b", "a", "c", "a"})
+	require.Equal(t, 3, ms.Count("a"))
+	require.Equal(t, 1, ms.Count("b"))
+	require.Equal(t, 0, ms.Count("d"))
+	require.True(t, ms.Contains("c"))
+	require.False(t, ms.Contains("d"))
+	require.Equal(t, 5, ms.TotalLen())
+	require.Equal(t, NewFromSlice([]string{"a", "b", "c"}), ms.Distinct())
+
+	ms.AddN("d", 2)
+	ms.AddN("d", 0)
+	ms.AddN("d", -3)
+	ms.Add("b")
+	ms.AddAll([]string{"c", "e"})
+	require.Equal(t, MultiSet[string]{"a": 3, "b": 2, "c": 2, "d": 2, "e": 1}, ms)
+
+	ms.Remove("e")
+	ms.Remove("x")
+	ms.RemoveN("a", 2)
+	ms.RemoveN("d", 5)
+	ms.RemoveN("b", -1)
+	ms.RemoveAll([]string{"c", "c", "c"})
+	require.Equal(t, MultiSet[string]{"a": 1, "b": 2}, ms)
+
+	actual := ms.ToSlice()
+	slices.Sort(actual)
+	require.Equal(t, []string{"a", "b", "b"}, actual)
+}
+
+func TestMultiSetConstructors(t *testing.T) {
+	require.Equal(t, MultiSet[int]{}, NewMultiSet[int]())
+	require.Equal(t, MultiSet[int]{}, NewMultiSetFromSlice([]int{}))
+	require.Equal(t, MultiSet[int]{1: 1, 2: 1}, NewMultiSetFromSet(NewFromSlice([]int{1, 2})))
+	require.Equal(t, MultiSet[int]{1: 3}, NewMultiSetFromCounts(map[int]int{1: 3, 2: 0, 3: -1}))
+}
+
+func TestMultiSetMostCommon(t *testing.T) {
+	ms := NewMultiSetFromSlice([]string{"x", "b", "a", "b", "c", "c", "c", "a"})
+	require.Equal(t, []Counted[string]{{"c", 3}}, ms.MostCommon(1))
+	require.Equal(t, []Counted[string]{{"c", 3}, {"a", 2}, {"b", 2}}, ms.MostCommon(3))
+	require.Equal(t, []Counted[string]{{"c", 3}, {"a", 2}, {"b", 2}, {"x", 1}}, ms.MostCommon(-1))
+	require.Equal(t, []Counted[string]{{"c", 3}, {"a", 2}, {"b", 2}, {"x", 1}}, ms.MostCommon(10))
+	require.Equal(t, []Counted[string]{}, ms.MostCommon(0))
+	require.Equal(t, []Counted[string]{}, NewMultiSet[string]().MostCommon(2))
+}
+
+func TestMultiSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          MultiSet[string]
+		other        MultiSet[string]
+		union        MultiSet[string]
+		sum          MultiSet[string]
+		intersection MultiSet[string]
+		difference   MultiSet[string]
+		equals       bool
+		subset       bool
+	}{
+		{
+			name:         "empty sets",
+			set:          MultiSet[string]{},
+			other:        MultiSet[string]{},
+			union:        MultiSet[string]{},
+			sum:          MultiSet[string]{},
+			intersection: MultiSet[string]{},
+			difference:   MultiSet[string]{},
+			equals:       true,
+			subset:       true,
+		},
+		{
+			name:         "equal sets",
+			set:          MultiSet[string]{"a": 2, "b": 1},
+			other:        MultiSet[string]{"a": 2, "b": 1},
+			union:        MultiSet[string]{"a": 2, "b": 1},
+			sum:          MultiSet[string]{"a": 4, "b": 2},
+			intersection: MultiSet[string]{"a": 2, "b": 1},
+			difference:   MultiSet[string]{},
+			equals:       true,
+			subset:       true,
+		},
+		{
+			name:         "same elements fewer occurrences",
+			set:          MultiSet[string]{"a": 1, "b": 1},
+			other:        MultiSet[string]{"a": 3, "b": 1},
+			union:        MultiSet[string]{"a": 3, "b": 1},
+			sum:          MultiSet[string]{"a": 4, "b": 2},
+			intersection: MultiSet[string]{"a": 1, "b": 1},
+			difference:   MultiSet[string]{},
+			equals:       false,
+			subset:       true,
+		},
+		{
+			name:         "partial overlap",
+			set:          MultiSet[string]{"a": 3, "b": 2},
+			other:        MultiSet[string]{"b": 5, "c": 1},
+			union:        MultiSet[string]{"a": 3, "b": 5, "c": 1},
+			sum:          MultiSet[string]{"a": 3, "b": 7, "c": 1},
+			intersection: MultiSet[string]{"b": 2},
+			difference:   MultiSet[string]{"a": 3},
+			equals:       false,
+			subset:       false,
+		},
+		{
+			name:         "more occurrences",
+			set:          MultiSet[string]{"a": 4},
+			other:        MultiSet[string]{"a": 1},
+			union:        MultiSet[string]{"a": 4},
+			sum:          MultiSet[string]{"a": 5},
+			intersection: MultiSet[string]{"a": 1},
+			difference:   MultiSet[string]{"a": 3},
+			equals:       false,
+			subset:       false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.union, c.set.Union(c.other))
+			require.Equal(t, c.sum, c.set.Sum(c.other))
+			require.Equal(t, c.intersection, c.set.Intersection(c.other))
+			require.Equal(t, c.difference, c.set.Difference(c.other))
+			require.Equal(t, c.equals, c.set.Equals(c.other))
+			require.Equal(t, c.subset, c.set.IsSubsetOf(c.other))
+		})
+	}
+}
@@ -0,0 +1,250 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+// OrderedSet is a generic, not threadsafe set data structure remembering the
+// order in which elements were first added. It combines a map with a doubly
+// linked list, so Add, Remove and Contains stay O(1).
+type OrderedSet[T comparable] struct {
+	index      map[T]*orderedNode[T]
+	head, tail *orderedNode[T]
+}
+
+type orderedNode[T comparable] struct {
+	item       T
+	prev, next *orderedNode[T]
+}
+
+// NewOrdered creates a new OrderedSet.
+func NewOrdered[T comparable]() *OrderedSet[T] {
+	return &OrderedSet[T]{index: make(map[T]*orderedNode[T])}
+}
+
+// NewOrderedFromSlice creates a new OrderedSet from a slice of comparable,
+// keeping the order of the first occurrence of each element.
+func NewOrderedFromSlice[T comparable](slice []T) *OrderedSet[T] {
+	set := NewOrdered[T]()
+	set.AddAll(slice)
+	return set
+}
+
+// Len returns the number of elements in an OrderedSet.
+func (set *OrderedSet[T]) Len() int {
+	return len(set.index)
+}
+
+// ToSlice returns a slice of elements from an OrderedSet in insertion order.
+func (set *OrderedSet[T]) ToSlice() []T {
+	slice := make([]T, 0, len(set.index))
+	for n := set.head; n != nil; n = n.next {
+		slice = append(slice, n.item)
+	}
+	return slice
+}
+
+// ToSet returns the elements of an OrderedSet as an unordered Set.
+func (set *OrderedSet[T]) ToSet() Set[T] {
+	return NewFromMapKeys(set.index)
+}
+
+// Each calls fn for every element of an OrderedSet in insertion order, until
+// fn returns false.
+func (set *OrderedSet[T]) Each(fn func(T) bool) {
+	for n := set.head; n != nil; n = n.next {
+		if !fn(n.item) {
+			return
+		}
+	}
+}
+
+// First returns the oldest element of an OrderedSet, or false if it is empty.
+func (set *OrderedSet[T]) First() (T, bool) {
+	var zero T
+	if set.head == nil {
+		return zero, false
+	}
+	return set.head.item, true
+}
+
+// Last returns the newest element of an OrderedSet, or false if it is empty.
+func (set *OrderedSet[T]) Last() (T, bool) {
+	var zero T
+	if set.tail == nil {
+		return zero, false
+	}
+	return set.tail.item, true
+}
+
+// Contains returns true if an OrderedSet contains an element.
+func (set *OrderedSet[T]) Contains(s T) bool {
+	_, ok := set.index[s]
+	return ok
+}
+
+// Add adds an element at the end of an OrderedSet. Adding an element already
+// present does not change its position.
+func (set *OrderedSet[T]) Add(s T) {
+	if _, ok := set.index[s]; ok {
+		return
+	}
+	n := &orderedNode[T]{item: s}
+	set.index[s] = n
+	set.pushBack(n)
+}
+
+// Remove removes an element from an OrderedSet.
+func (set *OrderedSet[T]) Remove(s T) {
+	n, ok := set.index[s]
+	if !ok {
+		return
+	}
+	delete(set.index, s)
+	set.unlink(n)
+}
+
+// AddAll adds a slice of elements to an OrderedSet.
+func (set *OrderedSet[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		set.Add(s)
+	}
+}
+
+// RemoveAll removes a slice of elements from an OrderedSet.
+func (set *OrderedSet[T]) RemoveAll(slice []T) {
+	for _, s := range slice {
+		set.Remove(s)
+	}
+}
+
+// MoveToEnd moves an element to the end of an OrderedSet, as if it was just
+// added. It returns false if the element is not present.
+func (set *OrderedSet[T]) MoveToEnd(s T) bool {
+	n, ok := set.index[s]
+	if !ok {
+		return false
+	}
+	if n != set.tail {
+		set.unlink(n)
+		set.pushBack(n)
+	}
+	return true
+}
+
+// Equals returns true if two OrderedSets hold the same elements, regardless
+// of their order.
+func (set *OrderedSet[T]) Equals(other *OrderedSet[T]) bool {
+	if len(set.index) != len(other.index) {
+		return false
+	}
+	for s := range set.index {
+		if _, ok := other.index[s]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubsetOf returns true if an OrderedSet is a subset of another OrderedSet
+// (they can be equal).
+func (set *OrderedSet[T]) IsSubsetOf(other *OrderedSet[T]) bool {
+	if len(set.index) > len(other.index) {
+		return false
+	}
+	for s := range set.index {
+		if _, ok := other.index[s]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProperSubsetOf returns true if an OrderedSet is a proper subset of another
+// OrderedSet (they cannot be equal).
+func (set *OrderedSet[T]) IsProperSubsetOf(other *OrderedSet[T]) bool {
+	return len(set.index) < len(other.index) && set.IsSubsetOf(other)
+}
+
+// Union returns the union of two OrderedSets as new OrderedSet. Elements of
+// the receiver come first, in their order, followed by the new elements of
+// other in their order.
+func (set *OrderedSet[T]) Union(other *OrderedSet[T]) *OrderedSet[T] {
+	result := NewOrdered[T]()
+	for n := set.head; n != nil; n = n.next {
+		result.Add(n.item)
+	}
+	for n := other.head; n != nil; n = n.next {
+		result.Add(n.item)
+	}
+	return result
+}
+
+// Intersection returns the intersection of two OrderedSets as new OrderedSet,
+// in the order of the receiver.
+func (set *OrderedSet[T]) Intersection(other *OrderedSet[T]) *OrderedSet[T] {
+	result := NewOrdered[T]()
+	for n := set.head; n != nil; n = n.next {
+		if _, ok := other.index[n.item]; ok {
+			result.Add(n.item)
+		}
+	}
+	return result
+}
+
+// Difference returns the difference of two OrderedSets as new OrderedSet, in
+// the order of the receiver.
+func (set *OrderedSet[T]) Difference(other *OrderedSet[T]) *OrderedSet[T] {
+	result := NewOrdered[T]()
+	for n := set.head; n != nil; n = n.next {
+		if _, ok := other.index[n.item]; !ok {
+			result.Add(n.item)
+		}
+	}
+	return result
+}
+
+func (set *OrderedSet[T]) pushBack(n *orderedNode[T]) {
+	n.prev, n.next = set.tail, nil
+	if set.tail == nil {
+		set.head = n
+	} else {
+		set.tail.next = n
+	}
+	set.tail = n
+}
+
+func (set *OrderedSet[T]) unlink(n *orderedNode[T]) {
+	if n.prev == nil {
+		set.head = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		set.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
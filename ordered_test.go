@@ -0,0 +1,185 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedSetBasic(t *testing.T) {
+	set := NewOrderedFromSlice([]string{"c", "a", "c", "b", "a"})
+	require.Equal(t, 3, set.Len())
+	require.Equal(t, []string{"c", "a", "b"}, set.ToSlice())
+	require.Equal(t, Set[string]{"a": struct{}{}, "b": struct{}{}, "c": struct{}{}}, set.ToSet())
+	require.True(t, set.Contains("a"))
+	require.False(t, set.Contains("d"))
+
+	set.Add("d")
+	set.Add("c")
+	require.Equal(t, []string{"c", "a", "b", "d"}, set.ToSlice())
+
+	set.Remove("a")
+	set.Remove("x")
+	require.Equal(t, []string{"c", "b", "d"}, set.ToSlice())
+
+	set.RemoveAll([]string{"c", "d"})
+	set.AddAll([]string{"e", "b", "f"})
+	require.Equal(t, []string{"b", "e", "f"}, set.ToSlice())
+
+	seen := make([]string, 0)
+	set.Each(func(s string) bool {
+		seen = append(seen, s)
+		return s != "e"
+	})
+	require.Equal(t, []string{"b", "e"}, seen)
+}
+
+func TestOrderedSetFirstLast(t *testing.T) {
+	set := NewOrdered[int]()
+	_, ok := set.First()
+	require.False(t, ok)
+	_, ok = set.Last()
+	require.False(t, ok)
+
+	set.AddAll([]int{3, 1, 2})
+	first, ok := set.First()
+	require.True(t, ok)
+	require.Equal(t, 3, first)
+	last, ok := set.Last()
+	require.True(t, ok)
+	require.Equal(t, 2, last)
+
+	set.RemoveAll([]int{3, 2})
+	first, _ = set.First()
+	last, _ = set.Last()
+	require.Equal(t, 1, first)
+	require.Equal(t, 1, last)
+
+	set.Remove(1)
+	_, ok = set.First()
+	require.False(t, ok)
+	require.Equal(t, []int{}, set.ToSlice())
+}
+
+func TestOrderedSetMoveToEnd(t *testing.T) {
+	cases := []struct {
+		name     string
+		set      []int
+		s        int
+		ok       bool
+		expected []int
+	}{
+		{name: "empty set", set: []int{}, s: 1, ok: false, expected: []int{}},
+		{name: "absent", set: []int{1, 2, 3}, s: 4, ok: false, expected: []int{1, 2, 3}},
+		{name: "first", set: []int{1, 2, 3}, s: 1, ok: true, expected: []int{2, 3, 1}},
+		{name: "middle", set: []int{1, 2, 3}, s: 2, ok: true, expected: []int{1, 3, 2}},
+		{name: "last", set: []int{1, 2, 3}, s: 3, ok: true, expected: []int{1, 2, 3}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			set := NewOrderedFromSlice(c.set)
+			require.Equal(t, c.ok, set.MoveToEnd(c.s))
+			require.Equal(t, c.expected, set.ToSlice())
+			if len(c.expected) > 0 {
+				last, _ := set.Last()
+				require.Equal(t, c.expected[len(c.expected)-1], last)
+			}
+		})
+	}
+}
+
+func TestOrderedSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          []string
+		other        []string
+		union        []string
+		intersection []string
+		difference   []string
+		equals       bool
+		subset       bool
+		properSubset bool
+	}{
+		{
+			name:         "empty sets",
+			set:          []string{},
+			other:        []string{},
+			union:        []string{},
+			intersection: []string{},
+			difference:   []string{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "same elements different order",
+			set:          []string{"a", "b", "c"},
+			other:        []string{"c", "b", "a"},
+			union:        []string{"a", "b", "c"},
+			intersection: []string{"a", "b", "c"},
+			difference:   []string{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "proper subset",
+			set:          []string{"c", "a"},
+			other:        []string{"a", "b", "c"},
+			union:        []string{"c", "a", "b"},
+			intersection: []string{"c", "a"},
+			difference:   []string{},
+			equals:       false,
+			subset:       true,
+			properSubset: true,
+		},
+		{
+			name:         "partial overlap",
+			set:          []string{"z", "b", "c"},
+			other:        []string{"d", "c", "b"},
+			union:        []string{"z", "b", "c", "d"},
+			intersection: []string{"b", "c"},
+			difference:   []string{"z"},
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			set := NewOrderedFromSlice(c.set)
+			other := NewOrderedFromSlice(c.other)
+			require.Equal(t, c.union, set.Union(other).ToSlice())
+			require.Equal(t, c.intersection, set.Intersection(other).ToSlice())
+			require.Equal(t, c.difference, set.Difference(other).ToSlice())
+			require.Equal(t, c.equals, set.Equals(other))
+			require.Equal(t, c.subset, set.IsSubsetOf(other))
+			require.Equal(t, c.properSubset, set.IsProperSubsetOf(other))
+		})
+	}
+}
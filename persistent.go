@@ -0,0 +1,433 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// PersistentSet is a generic immutable set data structure, backed by a hash
+// array mapped trie. With, Without and the set operations return new versions
+// in O(log n) per changed element, sharing structure with the old version
+// instead of copying it. Since versions never change, a PersistentSet is safe
+// for concurrent reads. Use Transient for batch edits.
+type PersistentSet[T comparable] struct {
+	root *hamtNode[T]
+	size int
+	hash func(T) uint64
+}
+
+const (
+	hamtBits  = 5
+	hamtWidth = 1 << hamtBits
+	hamtMask  = hamtWidth - 1
+)
+
+// hamtNode is a trie node. Entries are indexed by the popcount of bitmap
+// below their 5 bits of hash. Once the hash is exhausted, colliding items
+// are kept in a plain list.
+type hamtNode[T comparable] struct {
+	bitmap     uint32
+	entries    []hamtEntry[T]
+	collisions []T
+	// edit marks nodes owned by a Transient, which may change them in place.
+	edit *hamtEdit
+}
+
+// hamtEntry is either a child node or a single item.
+type hamtEntry[T comparable] struct {
+	node *hamtNode[T]
+	item T
+	hash uint64
+}
+
+// hamtEdit identifies a Transient. It must not be zero sized, as pointers to
+// distinct zero sized values may compare equal.
+type hamtEdit struct{ _ byte }
+
+// NewPersistent creates a new empty PersistentSet. Elements are hashed with
+// the default hasher of ShardedSet.
+func NewPersistent[T comparable]() PersistentSet[T] {
+	return NewPersistentWithHasher(defaultHasher[T]())
+}
+
+// NewPersistentWithHasher creates a new empty PersistentSet with a custom hash
+// function. Equal elements must have equal hashes. Versions derived from it
+// keep the hash function.
+func NewPersistentWithHasher[T comparable](hash func(T) uint64) PersistentSet[T] {
+	return PersistentSet[T]{root: &hamtNode[T]{}, hash: hash}
+}
+
+// NewPersistentFromSlice creates a new PersistentSet from a slice of
+// comparable.
+func NewPersistentFromSlice[T comparable](slice []T) PersistentSet[T] {
+	t := NewPersistent[T]().Transient()
+	for _, s := range slice {
+		t.Add(s)
+	}
+	return t.Persistent()
+}
+
+// NewPersistentFromSet creates a new PersistentSet from the elements of a Set.
+func NewPersistentFromSet[T comparable](set Set[T]) PersistentSet[T] {
+	t := NewPersistent[T]().Transient()
+	for s := range set {
+		t.Add(s)
+	}
+	return t.Persistent()
+}
+
+// Len returns the number of elements in a PersistentSet.
+func (p PersistentSet[T]) Len() int {
+	return p.size
+}
+
+// Contains returns true if a PersistentSet contains an element.
+func (p PersistentSet[T]) Contains(s T) bool {
+	if p.root == nil {
+		return false
+	}
+	return p.root.contains(s, p.hash(s), 0)
+}
+
+// With returns a new version of a PersistentSet holding an element.
+func (p PersistentSet[T]) With(s T) PersistentSet[T] {
+	p = p.init()
+	root, added := p.root.with(s, p.hash(s), 0, nil)
+	if !added {
+		return p
+	}
+	return PersistentSet[T]{root: root, size: p.size + 1, hash: p.hash}
+}
+
+// Without returns a new version of a PersistentSet without an element.
+func (p PersistentSet[T]) Without(s T) PersistentSet[T] {
+	if p.root == nil {
+		return p
+	}
+	root, removed := p.root.without(s, p.hash(s), 0, nil)
+	if !removed {
+		return p
+	}
+	return PersistentSet[T]{root: root, size: p.size - 1, hash: p.hash}
+}
+
+// All returns an iterator over the elements of a PersistentSet, in no
+// particular order.
+func (p PersistentSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if p.root != nil {
+			p.root.each(yield)
+		}
+	}
+}
+
+// ToSlice returns an unordered slice of elements from a PersistentSet.
+func (p PersistentSet[T]) ToSlice() []T {
+	slice := make([]T, 0, p.size)
+	for s := range p.All() {
+		slice = append(slice, s)
+	}
+	return slice
+}
+
+// ToSet returns the elements of a PersistentSet as a new Set.
+func (p PersistentSet[T]) ToSet() Set[T] {
+	set := make(Set[T], p.size)
+	for s := range p.All() {
+		set[s] = struct{}{}
+	}
+	return set
+}
+
+// Equals returns true if two PersistentSets are equal.
+func (p PersistentSet[T]) Equals(other PersistentSet[T]) bool {
+	return p.size == other.size && p.IsSubsetOf(other)
+}
+
+// IsSubsetOf returns true if a PersistentSet is a subset of another
+// PersistentSet (they can be equal).
+func (p PersistentSet[T]) IsSubsetOf(other PersistentSet[T]) bool {
+	if p.size > other.size {
+		return false
+	}
+	if p.root == other.root {
+		return true
+	}
+	for s := range p.All() {
+		if !other.Contains(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProperSubsetOf returns true if a PersistentSet is a proper subset of
+// another PersistentSet (they cannot be equal).
+func (p PersistentSet[T]) IsProperSubsetOf(other PersistentSet[T]) bool {
+	return p.size < other.size && p.IsSubsetOf(other)
+}
+
+// Union returns the union of two PersistentSets. The elements of the smaller
+// set are added to the larger one, so the result shares structure with it.
+func (p PersistentSet[T]) Union(other PersistentSet[T]) PersistentSet[T] {
+	large, small := p, other
+	if large.size < small.size {
+		large, small = small, large
+	}
+	t := large.init().Transient()
+	for s := range small.All() {
+		t.Add(s)
+	}
+	return t.Persistent()
+}
+
+// Intersection returns the intersection of two PersistentSets.
+func (p PersistentSet[T]) Intersection(other PersistentSet[T]) PersistentSet[T] {
+	small, large := p, other
+	if small.size > large.size {
+		small, large = large, small
+	}
+	t := PersistentSet[T]{root: &hamtNode[T]{}, hash: p.init().hash}.Transient()
+	for s := range small.All() {
+		if large.Contains(s) {
+			t.Add(s)
+		}
+	}
+	return t.Persistent()
+}
+
+// Difference returns the difference of two PersistentSets. The result shares
+// structure with the receiver.
+func (p PersistentSet[T]) Difference(other PersistentSet[T]) PersistentSet[T] {
+	t := p.init().Transient()
+	for s := range other.All() {
+		t.Remove(s)
+	}
+	return t.Persistent()
+}
+
+// init makes the zero PersistentSet usable.
+func (p PersistentSet[T]) init() PersistentSet[T] {
+	if p.hash == nil {
+		return NewPersistent[T]()
+	}
+	return p
+}
+
+// Transient returns a mutable builder starting from a PersistentSet. It
+// changes in place the nodes it has already copied, so a batch of edits is
+// much cheaper than the same sequence of With and Without calls. The
+// original PersistentSet is never modified.
+func (p PersistentSet[T]) Transient() *Transient[T] {
+	return &Transient[T]{set: p.init(), edit: &hamtEdit{}}
+}
+
+// Transient is a not threadsafe builder for a PersistentSet.
+type Transient[T comparable] struct {
+	set  PersistentSet[T]
+	edit *hamtEdit
+}
+
+// Len returns the number of elements in a Transient.
+func (t *Transient[T]) Len() int {
+	return t.set.size
+}
+
+// Contains returns true if a Transient contains an element.
+func (t *Transient[T]) Contains(s T) bool {
+	return t.set.Contains(s)
+}
+
+// Add adds an element to a Transient.
+func (t *Transient[T]) Add(s T) {
+	root, added := t.set.root.with(s, t.set.hash(s), 0, t.edit)
+	t.set.root = root
+	if added {
+		t.set.size++
+	}
+}
+
+// Remove removes an element from a Transient.
+func (t *Transient[T]) Remove(s T) {
+	root, removed := t.set.root.without(s, t.set.hash(s), 0, t.edit)
+	t.set.root = root
+	if removed {
+		t.set.size--
+	}
+}
+
+// Persistent returns the content of a Transient as a PersistentSet. The
+// Transient can still be used afterwards, further edits copy nodes again
+// and do not affect the returned set.
+func (t *Transient[T]) Persistent() PersistentSet[T] {
+	t.edit = &hamtEdit{}
+	return t.set
+}
+
+func (n *hamtNode[T]) contains(s T, hash uint64, shift uint) bool {
+	for {
+		if shift >= 64 {
+			for _, c := range n.collisions {
+				if c == s {
+					return true
+				}
+			}
+			return false
+		}
+		bit := uint32(1) << ((hash >> shift) & hamtMask)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		e := &n.entries[bits.OnesCount32(n.bitmap&(bit-1))]
+		if e.node == nil {
+			return e.hash == hash && e.item == s
+		}
+		n = e.node
+		shift += hamtBits
+	}
+}
+
+// editable returns n itself if it is owned by edit, or a copy owned by edit.
+func (n *hamtNode[T]) editable(edit *hamtEdit) *hamtNode[T] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &hamtNode[T]{
+		bitmap:     n.bitmap,
+		entries:    append([]hamtEntry[T](nil), n.entries...),
+		collisions: append([]T(nil), n.collisions...),
+		edit:       edit,
+	}
+}
+
+func (n *hamtNode[T]) with(s T, hash uint64, shift uint, edit *hamtEdit) (*hamtNode[T], bool) {
+	if shift >= 64 {
+		for _, c := range n.collisions {
+			if c == s {
+				return n, false
+			}
+		}
+		m := n.editable(edit)
+		m.collisions = append(m.collisions, s)
+		return m, true
+	}
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	i := bits.OnesCount32(n.bitmap & (bit - 1))
+	if n.bitmap&bit == 0 {
+		m := n.editable(edit)
+		m.bitmap |= bit
+		m.entries = append(m.entries, hamtEntry[T]{})
+		copy(m.entries[i+1:], m.entries[i:])
+		m.entries[i] = hamtEntry[T]{item: s, hash: hash}
+		return m, true
+	}
+	e := n.entries[i]
+	if e.node == nil {
+		if e.hash == hash && e.item == s {
+			return n, false
+		}
+		// push the existing item one level down, next to the new one
+		child := &hamtNode[T]{edit: edit}
+		child, _ = child.with(e.item, e.hash, shift+hamtBits, edit)
+		child, _ = child.with(s, hash, shift+hamtBits, edit)
+		m := n.editable(edit)
+		m.entries[i] = hamtEntry[T]{node: child}
+		return m, true
+	}
+	child, added := e.node.with(s, hash, shift+hamtBits, edit)
+	if !added {
+		return n, false
+	}
+	m := n.editable(edit)
+	m.entries[i] = hamtEntry[T]{node: child}
+	return m, true
+}
+
+func (n *hamtNode[T]) without(s T, hash uint64, shift uint, edit *hamtEdit) (*hamtNode[T], bool) {
+	if shift >= 64 {
+		for i, c := range n.collisions {
+			if c == s {
+				m := n.editable(edit)
+				m.collisions = append(m.collisions[:i], m.collisions[i+1:]...)
+				return m, true
+			}
+		}
+		return n, false
+	}
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := bits.OnesCount32(n.bitmap & (bit - 1))
+	e := n.entries[i]
+	if e.node == nil {
+		if e.hash != hash || e.item != s {
+			return n, false
+		}
+		m := n.editable(edit)
+		m.bitmap &^= bit
+		m.entries = append(m.entries[:i], m.entries[i+1:]...)
+		return m, true
+	}
+	child, removed := e.node.without(s, hash, shift+hamtBits, edit)
+	if !removed {
+		return n, false
+	}
+	m := n.editable(edit)
+	switch {
+	case child.bitmap == 0 && len(child.collisions) == 0:
+		m.bitmap &^= bit
+		m.entries = append(m.entries[:i], m.entries[i+1:]...)
+	case len(child.entries) == 1 && child.entries[0].node == nil:
+		// a lone item moves back up
+		m.entries[i] = child.entries[0]
+	case shift+hamtBits >= 64 && len(child.collisions) == 1:
+		m.entries[i] = hamtEntry[T]{item: child.collisions[0], hash: hash}
+	default:
+		m.entries[i] = hamtEntry[T]{node: child}
+	}
+	return m, true
+}
+
+func (n *hamtNode[T]) each(yield func(T) bool) bool {
+	for _, c := range n.collisions {
+		if !yield(c) {
+			return false
+		}
+	}
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.each(yield) {
+				return false
+			}
+		} else if !yield(e.item) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,222 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentSetBasic(t *testing.T) {
+	var empty PersistentSet[string]
+	require.Equal(t, 0, empty.Len())
+	require.False(t, empty.Contains("a"))
+	require.Equal(t, 0, empty.Without("a").Len())
+
+	v1 := empty.With("a")
+	v2 := v1.With("b").With("c")
+	v3 := v2.Without("a")
+	require.Equal(t, 0, empty.Len())
+	require.Equal(t, Set[string]{"a": struct{}{}}, v1.ToSet())
+	require.Equal(t, Set[string]{"a": struct{}{}, "b": struct{}{}, "c": struct{}{}}, v2.ToSet())
+	require.Equal(t, Set[string]{"b": struct{}{}, "c": struct{}{}}, v3.ToSet())
+	require.ElementsMatch(t, []string{"b", "c"}, v3.ToSlice())
+
+	require.Equal(t, v2.root, v2.With("a").root, "adding a present element returns the same version")
+	require.Equal(t, v2.root, v2.Without("x").root, "removing an absent element returns the same version")
+}
+
+func TestPersistentSetConversion(t *testing.T) {
+	set := NewFromSlice([]int{1, 2, 3})
+	p := NewPersistentFromSet(set)
+	require.Equal(t, set, p.ToSet())
+	require.Equal(t, set, NewPersistentFromSlice([]int{3, 2, 1, 2}).ToSet())
+	require.Len(t, firstN(p.All(), 2), 2)
+}
+
+func TestPersistentSetStructuralSharing(t *testing.T) {
+	base := NewPersistentFromSlice(randomInts(setSize))
+	next := base.With(-1)
+	shared := 0
+	for i := range base.root.entries {
+		if base.root.entries[i].node != nil && base.root.entries[i].node == next.root.entries[i].node {
+			shared++
+		}
+	}
+	// only the path to the new element is copied
+	require.GreaterOrEqual(t, shared, len(base.root.entries)-2)
+	require.False(t, base.Contains(-1))
+	require.True(t, next.Contains(-1))
+}
+
+func TestPersistentSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          []string
+		other        []string
+		union        Set[string]
+		intersection Set[string]
+		difference   Set[string]
+		equals       bool
+		subset       bool
+		properSubset bool
+	}{
+		{
+			name:         "empty sets",
+			set:          []string{},
+			other:        []string{},
+			union:        Set[string]{},
+			intersection: Set[string]{},
+			difference:   Set[string]{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "proper subset",
+			set:          []string{"a"},
+			other:        []string{"a", "b"},
+			union:        NewFromSlice([]string{"a", "b"}),
+			intersection: NewFromSlice([]string{"a"}),
+			difference:   Set[string]{},
+			equals:       false,
+			subset:       true,
+			properSubset: true,
+		},
+		{
+			name:         "partial overlap",
+			set:          []string{"a", "b", "c"},
+			other:        []string{"b", "c", "d"},
+			union:        NewFromSlice([]string{"a", "b", "c", "d"}),
+			intersection: NewFromSlice([]string{"b", "c"}),
+			difference:   NewFromSlice([]string{"a"}),
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p := NewPersistentFromSlice(c.set)
+			other := NewPersistentFromSlice(c.other)
+			require.Equal(t, c.union, p.Union(other).ToSet())
+			require.Equal(t, c.intersection, p.Intersection(other).ToSet())
+			require.Equal(t, c.difference, p.Difference(other).ToSet())
+			require.Equal(t, c.equals, p.Equals(other))
+			require.Equal(t, c.subset, p.IsSubsetOf(other))
+			require.Equal(t, c.properSubset, p.IsProperSubsetOf(other))
+			// operands are never modified
+			require.Equal(t, NewFromSlice(c.set), p.ToSet())
+			require.Equal(t, NewFromSlice(c.other), other.ToSet())
+		})
+	}
+}
+
+func TestPersistentSetTransient(t *testing.T) {
+	base := NewPersistentFromSlice([]int{1, 2, 3})
+	tr := base.Transient()
+	tr.Add(4)
+	tr.Add(4)
+	tr.Remove(1)
+	tr.Remove(42)
+	require.Equal(t, 3, tr.Len())
+	require.True(t, tr.Contains(4))
+
+	v1 := tr.Persistent()
+	tr.Add(5)
+	v2 := tr.Persistent()
+
+	require.Equal(t, NewFromSlice([]int{1, 2, 3}), base.ToSet())
+	require.Equal(t, NewFromSlice([]int{2, 3, 4}), v1.ToSet())
+	require.Equal(t, NewFromSlice([]int{2, 3, 4, 5}), v2.ToSet())
+}
+
+func TestPersistentSetCollisions(t *testing.T) {
+	// a weak hash forces items down to the collision lists
+	weak := NewPersistentWithHasher(func(s int) uint64 { return uint64(s % 3) })
+	plain := New[int]()
+	p := weak
+	for i := 0; i < 30; i++ {
+		p = p.With(i)
+		plain.Add(i)
+	}
+	require.Equal(t, plain, p.ToSet())
+	for i := 0; i < 30; i += 2 {
+		p = p.Without(i)
+		plain.Remove(i)
+		require.Equal(t, plain, p.ToSet())
+	}
+	union := p.Union(NewPersistentFromSlice([]int{0, 1, 2}))
+	require.Equal(t, plain.Union(NewFromSlice([]int{0, 2})), union.ToSet())
+	for i := 1; i < 30; i += 2 {
+		require.True(t, p.Contains(i))
+		p = p.Without(i)
+	}
+	require.Equal(t, 0, p.Len())
+}
+
+func TestPersistentSetCompositeZeroKeys(t *testing.T) {
+	type fp struct{ X float64 }
+	zero := 0.0
+	p := NewPersistentFromSlice([]fp{{-zero}})
+	require.True(t, p.Contains(fp{zero}))
+	require.Equal(t, 1, p.With(fp{zero}).Len())
+	require.Equal(t, 0, p.Without(fp{zero}).Len())
+	require.Equal(t, NewFromSlice([]fp{{zero}}), p.ToSet())
+}
+
+func TestPersistentSetMatchesSet(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	plain := New[int]()
+	p := NewPersistent[int]()
+	tr := NewPersistent[int]().Transient()
+	versions := make([]PersistentSet[int], 0)
+	snapshots := make([]Set[int], 0)
+	for i := 0; i < 5000; i++ {
+		v := rnd.Intn(1000)
+		if rnd.Intn(3) == 0 {
+			plain.Remove(v)
+			p = p.Without(v)
+			tr.Remove(v)
+		} else {
+			plain.Add(v)
+			p = p.With(v)
+			tr.Add(v)
+		}
+		if i%500 == 0 {
+			versions = append(versions, p)
+			snapshots = append(snapshots, plain.clone())
+		}
+	}
+	require.Equal(t, plain, p.ToSet())
+	require.Equal(t, len(plain), p.Len())
+	require.Equal(t, plain, tr.Persistent().ToSet())
+	for i, v := range versions {
+		require.Equal(t, snapshots[i], v.ToSet())
+	}
+}
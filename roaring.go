@@ -0,0 +1,697 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math/bits"
+	"slices"
+	"sort"
+)
+
+// RoaringSet is a not threadsafe compressed set of uint32, in the style of
+// Roaring bitmaps. Elements are grouped by their high 16 bits, and each group
+// is stored in the most compact of three containers: a sorted array for
+// sparse groups, a 65536 bit bitmap for dense groups, or a list of runs for
+// groups of consecutive values (see RunOptimize). The zero value is an empty
+// RoaringSet ready to use.
+type RoaringSet struct {
+	keys       []uint16
+	containers []*roaringContainer
+}
+
+const (
+	roaringArray  = 1
+	roaringBitmap = 2
+	roaringRun    = 3
+
+	// roaringMaxArray is the largest cardinality stored in an array container.
+	roaringMaxArray = 4096
+	roaringWords    = 1 << 16 / 64
+
+	roaringVersion = 1
+)
+
+type roaringContainer struct {
+	kind   byte
+	card   int
+	array  []uint16          // sorted, for roaringArray
+	bitmap []uint64          // roaringWords long, for roaringBitmap
+	runs   []roaringInterval // sorted and disjoint, for roaringRun
+}
+
+// roaringInterval is a run of consecutive values from start to last, inclusive.
+type roaringInterval struct {
+	start, last uint16
+}
+
+// NewRoaring creates a new RoaringSet.
+func NewRoaring() *RoaringSet {
+	return &RoaringSet{}
+}
+
+// NewRoaringFromSlice creates a new RoaringSet from a slice of elements.
+func NewRoaringFromSlice(slice []uint32) *RoaringSet {
+	r := NewRoaring()
+	r.AddAll(slice)
+	return r
+}
+
+// NewRoaringFromSet creates a new RoaringSet from the elements of a Set.
+func NewRoaringFromSet(set Set[uint32]) *RoaringSet {
+	r := NewRoaring()
+	for s := range set {
+		r.Add(s)
+	}
+	return r
+}
+
+// Len returns the number of elements in a RoaringSet.
+func (r *RoaringSet) Len() int {
+	n := 0
+	for _, c := range r.containers {
+		n += c.card
+	}
+	return n
+}
+
+// ToSlice returns an ascending slice of elements from a RoaringSet.
+func (r *RoaringSet) ToSlice() []uint32 {
+	slice := make([]uint32, 0, r.Len())
+	for s := range r.All() {
+		slice = append(slice, s)
+	}
+	return slice
+}
+
+// ToSet returns the elements of a RoaringSet as a Set.
+func (r *RoaringSet) ToSet() Set[uint32] {
+	set := make(Set[uint32], r.Len())
+	for s := range r.All() {
+		set[s] = struct{}{}
+	}
+	return set
+}
+
+// All returns an iterator over the elements of a RoaringSet in ascending
+// order.
+func (r *RoaringSet) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range r.containers {
+			high := uint32(r.keys[i]) << 16
+			if !c.each(func(low uint16) bool { return yield(high | uint32(low)) }) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if a RoaringSet contains an element.
+func (r *RoaringSet) Contains(s uint32) bool {
+	i, ok := r.find(uint16(s >> 16))
+	return ok && r.containers[i].contains(uint16(s))
+}
+
+// Add adds an element to a RoaringSet.
+func (r *RoaringSet) Add(s uint32) {
+	key := uint16(s >> 16)
+	i, ok := r.find(key)
+	if !ok {
+		r.keys = slices.Insert(r.keys, i, key)
+		r.containers = slices.Insert(r.containers, i, &roaringContainer{kind: roaringArray})
+	}
+	r.containers[i].add(uint16(s))
+}
+
+// Remove removes an element from a RoaringSet.
+func (r *RoaringSet) Remove(s uint32) {
+	i, ok := r.find(uint16(s >> 16))
+	if !ok {
+		return
+	}
+	c := r.containers[i]
+	c.remove(uint16(s))
+	if c.card == 0 {
+		r.keys = slices.Delete(r.keys, i, i+1)
+		r.containers = slices.Delete(r.containers, i, i+1)
+	}
+}
+
+// AddAll adds a slice of elements to a RoaringSet.
+func (r *RoaringSet) AddAll(slice []uint32) {
+	for _, s := range slice {
+		r.Add(s)
+	}
+}
+
+// RemoveAll removes a slice of elements from a RoaringSet.
+func (r *RoaringSet) RemoveAll(slice []uint32) {
+	for _, s := range slice {
+		r.Remove(s)
+	}
+}
+
+// RunOptimize converts containers to run containers wherever this makes them
+// smaller. It is worth calling on sets holding long ranges of consecutive
+// values, before serializing them or keeping them around.
+func (r *RoaringSet) RunOptimize() {
+	for _, c := range r.containers {
+		c.runOptimize()
+	}
+}
+
+// Equals returns true if two RoaringSets are equal.
+func (r *RoaringSet) Equals(other *RoaringSet) bool {
+	if !slices.Equal(r.keys, other.keys) {
+		return false
+	}
+	for i, c := range r.containers {
+		o := other.containers[i]
+		if c.card != o.card || intersectionCard(c, o) != c.card {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubsetOf returns true if a RoaringSet is a subset of another RoaringSet
+// (they can be equal).
+func (r *RoaringSet) IsSubsetOf(other *RoaringSet) bool {
+	for i, c := range r.containers {
+		j, ok := other.find(r.keys[i])
+		if !ok {
+			return false
+		}
+		o := other.containers[j]
+		if c.card > o.card || intersectionCard(c, o) != c.card {
+			return false
+		}
+	}
+	return true
+}
+
+// IsProperSubsetOf returns true if a RoaringSet is a proper subset of another
+// RoaringSet (they cannot be equal).
+func (r *RoaringSet) IsProperSubsetOf(other *RoaringSet) bool {
+	return r.Len() < other.Len() && r.IsSubsetOf(other)
+}
+
+// Union returns the union of two RoaringSets as new RoaringSet.
+func (r *RoaringSet) Union(other *RoaringSet) *RoaringSet {
+	result := &RoaringSet{}
+	i, j := 0, 0
+	for i < len(r.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || (i < len(r.keys) && r.keys[i] < other.keys[j]):
+			result.push(r.keys[i], r.containers[i].clone())
+			i++
+		case i == len(r.keys) || other.keys[j] < r.keys[i]:
+			result.push(other.keys[j], other.containers[j].clone())
+			j++
+		default:
+			result.push(r.keys[i], unionContainers(r.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Intersection returns the intersection of two RoaringSets as new RoaringSet.
+func (r *RoaringSet) Intersection(other *RoaringSet) *RoaringSet {
+	result := &RoaringSet{}
+	for i, key := range r.keys {
+		if j, ok := other.find(key); ok {
+			result.push(key, intersectContainers(r.containers[i], other.containers[j]))
+		}
+	}
+	return result
+}
+
+// IntersectionLen returns the number of elements in the intersection of two
+// RoaringSets, without materializing it.
+func (r *RoaringSet) IntersectionLen(other *RoaringSet) int {
+	n := 0
+	for i, key := range r.keys {
+		if j, ok := other.find(key); ok {
+			n += intersectionCard(r.containers[i], other.containers[j])
+		}
+	}
+	return n
+}
+
+// Difference returns the difference of two RoaringSets as new RoaringSet.
+func (r *RoaringSet) Difference(other *RoaringSet) *RoaringSet {
+	result := &RoaringSet{}
+	for i, key := range r.keys {
+		if j, ok := other.find(key); ok {
+			result.push(key, differenceContainers(r.containers[i], other.containers[j]))
+		} else {
+			result.push(key, r.containers[i].clone())
+		}
+	}
+	return result
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The format is a version
+// byte, the uvarint number of containers, then for each container its key as
+// little endian uint16, its kind byte and its content: a uvarint length and
+// little endian uint16 values for arrays, 1024 little endian uint64 words for
+// bitmaps, and a uvarint count of little endian uint16 start and last pairs
+// for runs.
+func (r *RoaringSet) MarshalBinary() ([]byte, error) {
+	buf := []byte{roaringVersion}
+	buf = binary.AppendUvarint(buf, uint64(len(r.keys)))
+	for i, c := range r.containers {
+		buf = binary.LittleEndian.AppendUint16(buf, r.keys[i])
+		buf = append(buf, c.kind)
+		switch c.kind {
+		case roaringArray:
+			buf = binary.AppendUvarint(buf, uint64(len(c.array)))
+			for _, v := range c.array {
+				buf = binary.LittleEndian.AppendUint16(buf, v)
+			}
+		case roaringBitmap:
+			for _, w := range c.bitmap {
+				buf = binary.LittleEndian.AppendUint64(buf, w)
+			}
+		case roaringRun:
+			buf = binary.AppendUvarint(buf, uint64(len(c.runs)))
+			for _, run := range c.runs {
+				buf = binary.LittleEndian.AppendUint16(buf, run.start)
+				buf = binary.LittleEndian.AppendUint16(buf, run.last)
+			}
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It replaces the
+// content of a RoaringSet with the decoded elements.
+func (r *RoaringSet) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != roaringVersion {
+		return fmt.Errorf("%w: unsupported roaring version", ErrInvalidEncoding)
+	}
+	data = data[1:]
+	n, err := readUvarint(&data)
+	if err != nil {
+		return err
+	}
+	// every container takes at least 4 bytes, this bounds the allocation
+	if n > uint64(len(data))/4 {
+		return fmt.Errorf("%w: %d containers do not fit in %d bytes", ErrInvalidEncoding, n, len(data))
+	}
+	result := RoaringSet{
+		keys:       make([]uint16, 0, n),
+		containers: make([]*roaringContainer, 0, n),
+	}
+	for i := uint64(0); i < n; i++ {
+		if len(data) < 3 {
+			return fmt.Errorf("%w: truncated container header", ErrInvalidEncoding)
+		}
+		key := binary.LittleEndian.Uint16(data)
+		if len(result.keys) > 0 && key <= result.keys[len(result.keys)-1] {
+			return fmt.Errorf("%w: container keys out of order", ErrInvalidEncoding)
+		}
+		c := &roaringContainer{kind: data[2]}
+		data = data[3:]
+		if err := c.decode(&data); err != nil {
+			return err
+		}
+		result.keys = append(result.keys, key)
+		result.containers = append(result.containers, c)
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidEncoding, len(data))
+	}
+	*r = result
+	return nil
+}
+
+// find returns the index of key in r.keys, or the index where it would be
+// inserted and false.
+func (r *RoaringSet) find(key uint16) (int, bool) {
+	return slices.BinarySearch(r.keys, key)
+}
+
+// push appends a container with a key greater than all current ones, unless
+// it is empty.
+func (r *RoaringSet) push(key uint16, c *roaringContainer) {
+	if c != nil && c.card > 0 {
+		r.keys = append(r.keys, key)
+		r.containers = append(r.containers, c)
+	}
+}
+
+func (c *roaringContainer) decode(data *[]byte) error {
+	switch c.kind {
+	case roaringArray:
+		n, err := readUvarint(data)
+		if err != nil {
+			return err
+		}
+		if n == 0 || n > roaringMaxArray || n*2 > uint64(len(*data)) {
+			return fmt.Errorf("%w: bad array container length %d", ErrInvalidEncoding, n)
+		}
+		c.array = make([]uint16, n)
+		for i := range c.array {
+			c.array[i] = binary.LittleEndian.Uint16((*data)[2*i:])
+			if i > 0 && c.array[i] <= c.array[i-1] {
+				return fmt.Errorf("%w: array container out of order", ErrInvalidEncoding)
+			}
+		}
+		*data = (*data)[2*n:]
+		c.card = int(n)
+	case roaringBitmap:
+		if len(*data) < roaringWords*8 {
+			return fmt.Errorf("%w: truncated bitmap container", ErrInvalidEncoding)
+		}
+		c.bitmap = make([]uint64, roaringWords)
+		for i := range c.bitmap {
+			c.bitmap[i] = binary.LittleEndian.Uint64((*data)[8*i:])
+			c.card += bits.OnesCount64(c.bitmap[i])
+		}
+		*data = (*data)[roaringWords*8:]
+		if c.card <= roaringMaxArray {
+			return fmt.Errorf("%w: sparse bitmap container", ErrInvalidEncoding)
+		}
+	case roaringRun:
+		n, err := readUvarint(data)
+		if err != nil {
+			return err
+		}
+		if n == 0 || n*4 > uint64(len(*data)) {
+			return fmt.Errorf("%w: bad run container length %d", ErrInvalidEncoding, n)
+		}
+		c.runs = make([]roaringInterval, n)
+		for i := range c.runs {
+			run := roaringInterval{
+				start: binary.LittleEndian.Uint16((*data)[4*i:]),
+				last:  binary.LittleEndian.Uint16((*data)[4*i+2:]),
+			}
+			// runs must be disjoint and not adjacent, so the encoding is canonical
+			if run.last < run.start || (i > 0 && uint32(run.start) <= uint32(c.runs[i-1].last)+1) {
+				return fmt.Errorf("%w: bad run container", ErrInvalidEncoding)
+			}
+			c.runs[i] = run
+			c.card += int(run.last-run.start) + 1
+		}
+		*data = (*data)[4*n:]
+	default:
+		return fmt.Errorf("%w: unknown container kind %d", ErrInvalidEncoding, c.kind)
+	}
+	return nil
+}
+
+func (c *roaringContainer) clone() *roaringContainer {
+	return &roaringContainer{
+		kind:   c.kind,
+		card:   c.card,
+		array:  slices.Clone(c.array),
+		bitmap: slices.Clone(c.bitmap),
+		runs:   slices.Clone(c.runs),
+	}
+}
+
+func (c *roaringContainer) contains(x uint16) bool {
+	switch c.kind {
+	case roaringArray:
+		_, ok := slices.BinarySearch(c.array, x)
+		return ok
+	case roaringBitmap:
+		return c.bitmap[x/64]&(1<<(x%64)) != 0
+	default:
+		i := sort.Search(len(c.runs), func(i int) bool { return c.runs[i].last >= x })
+		return i < len(c.runs) && c.runs[i].start <= x
+	}
+}
+
+func (c *roaringContainer) add(x uint16) {
+	switch c.kind {
+	case roaringArray:
+		i, ok := slices.BinarySearch(c.array, x)
+		if ok {
+			return
+		}
+		if len(c.array) == roaringMaxArray {
+			words := c.toBitmap()
+			words[x/64] |= 1 << (x % 64)
+			*c = roaringContainer{kind: roaringBitmap, card: roaringMaxArray + 1, bitmap: words}
+			return
+		}
+		c.array = slices.Insert(c.array, i, x)
+		c.card++
+	case roaringBitmap:
+		if c.bitmap[x/64]&(1<<(x%64)) == 0 {
+			c.bitmap[x/64] |= 1 << (x % 64)
+			c.card++
+		}
+	default:
+		if !c.contains(x) {
+			c.setBitmap(c.toBitmap())
+			c.add(x)
+		}
+	}
+}
+
+func (c *roaringContainer) remove(x uint16) {
+	switch c.kind {
+	case roaringArray:
+		if i, ok := slices.BinarySearch(c.array, x); ok {
+			c.array = slices.Delete(c.array, i, i+1)
+			c.card--
+		}
+	case roaringBitmap:
+		if c.bitmap[x/64]&(1<<(x%64)) != 0 {
+			c.bitmap[x/64] &^= 1 << (x % 64)
+			c.card--
+			if c.card <= roaringMaxArray {
+				c.setBitmap(c.bitmap)
+			}
+		}
+	default:
+		if c.contains(x) {
+			c.setBitmap(c.toBitmap())
+			c.remove(x)
+		}
+	}
+}
+
+func (c *roaringContainer) each(fn func(uint16) bool) bool {
+	switch c.kind {
+	case roaringArray:
+		for _, v := range c.array {
+			if !fn(v) {
+				return false
+			}
+		}
+	case roaringBitmap:
+		for i, w := range c.bitmap {
+			for w != 0 {
+				if !fn(uint16(i*64 + bits.TrailingZeros64(w))) {
+					return false
+				}
+				w &= w - 1
+			}
+		}
+	default:
+		for _, run := range c.runs {
+			for v := uint32(run.start); v <= uint32(run.last); v++ {
+				if !fn(uint16(v)) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// toBitmap returns the content of a container as a new bitmap.
+func (c *roaringContainer) toBitmap() []uint64 {
+	words := make([]uint64, roaringWords)
+	switch c.kind {
+	case roaringBitmap:
+		copy(words, c.bitmap)
+	case roaringArray:
+		for _, v := range c.array {
+			words[v/64] |= 1 << (v % 64)
+		}
+	default:
+		for _, run := range c.runs {
+			for v := uint32(run.start); v <= uint32(run.last); v++ {
+				words[v/64] |= 1 << (v % 64)
+			}
+		}
+	}
+	return words
+}
+
+// setBitmap replaces the content of a container with a bitmap, stored as an
+// array container if it is sparse enough.
+func (c *roaringContainer) setBitmap(words []uint64) {
+	card := 0
+	for _, w := range words {
+		card += bits.OnesCount64(w)
+	}
+	*c = roaringContainer{card: card}
+	if card > roaringMaxArray {
+		c.kind = roaringBitmap
+		c.bitmap = words
+		return
+	}
+	c.kind = roaringArray
+	c.array = make([]uint16, 0, card)
+	for i, w := range words {
+		for w != 0 {
+			c.array = append(c.array, uint16(i*64+bits.TrailingZeros64(w)))
+			w &= w - 1
+		}
+	}
+}
+
+func (c *roaringContainer) runOptimize() {
+	runs := make([]roaringInterval, 0)
+	c.each(func(v uint16) bool {
+		if n := len(runs); n > 0 && uint32(runs[n-1].last)+1 == uint32(v) {
+			runs[n-1].last = v
+		} else {
+			runs = append(runs, roaringInterval{start: v, last: v})
+		}
+		return true
+	})
+	// serialized sizes: 4 bytes per run, 2 per array value, 8192 per bitmap
+	size := 2 * c.card
+	if c.kind == roaringBitmap {
+		size = roaringWords * 8
+	}
+	if 4*len(runs) < size {
+		*c = roaringContainer{kind: roaringRun, card: c.card, runs: runs}
+	}
+}
+
+func fromBitmap(words []uint64) *roaringContainer {
+	c := &roaringContainer{}
+	c.setBitmap(words)
+	return c
+}
+
+func unionContainers(a, b *roaringContainer) *roaringContainer {
+	if a.kind == roaringArray && b.kind == roaringArray && a.card+b.card <= roaringMaxArray {
+		array := make([]uint16, 0, a.card+b.card)
+		i, j := 0, 0
+		for i < len(a.array) && j < len(b.array) {
+			switch {
+			case a.array[i] < b.array[j]:
+				array = append(array, a.array[i])
+				i++
+			case a.array[i] > b.array[j]:
+				array = append(array, b.array[j])
+				j++
+			default:
+				array = append(array, a.array[i])
+				i++
+				j++
+			}
+		}
+		array = append(array, a.array[i:]...)
+		array = append(array, b.array[j:]...)
+		return &roaringContainer{kind: roaringArray, card: len(array), array: array}
+	}
+	words := a.toBitmap()
+	b.each(func(v uint16) bool {
+		words[v/64] |= 1 << (v % 64)
+		return true
+	})
+	return fromBitmap(words)
+}
+
+func intersectContainers(a, b *roaringContainer) *roaringContainer {
+	if a.kind == roaringArray || b.kind == roaringArray {
+		if b.kind == roaringArray && a.kind != roaringArray {
+			a, b = b, a
+		}
+		array := make([]uint16, 0)
+		for _, v := range a.array {
+			if b.contains(v) {
+				array = append(array, v)
+			}
+		}
+		return &roaringContainer{kind: roaringArray, card: len(array), array: array}
+	}
+	words := a.toBitmap()
+	other := b.toBitmap()
+	for i := range words {
+		words[i] &= other[i]
+	}
+	return fromBitmap(words)
+}
+
+func intersectionCard(a, b *roaringContainer) int {
+	if a.kind == roaringArray || b.kind == roaringArray {
+		if b.kind == roaringArray && a.kind != roaringArray {
+			a, b = b, a
+		}
+		n := 0
+		for _, v := range a.array {
+			if b.contains(v) {
+				n++
+			}
+		}
+		return n
+	}
+	words, other := a.bitmap, b.bitmap
+	if a.kind != roaringBitmap {
+		words = a.toBitmap()
+	}
+	if b.kind != roaringBitmap {
+		other = b.toBitmap()
+	}
+	n := 0
+	for i := range words {
+		n += bits.OnesCount64(words[i] & other[i])
+	}
+	return n
+}
+
+func differenceContainers(a, b *roaringContainer) *roaringContainer {
+	if a.kind == roaringArray {
+		array := make([]uint16, 0, a.card)
+		for _, v := range a.array {
+			if !b.contains(v) {
+				array = append(array, v)
+			}
+		}
+		return &roaringContainer{kind: roaringArray, card: len(array), array: array}
+	}
+	words := a.toBitmap()
+	b.each(func(v uint16) bool {
+		words[v/64] &^= 1 << (v % 64)
+		return true
+	})
+	return fromBitmap(words)
+}
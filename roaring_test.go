@@ -0,0 +1,316 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+// roaringInput generates Set[uint32] values mixing sparse elements, dense
+// blocks and runs, so every container kind is exercised.
+type roaringInput Set[uint32]
+
+func (roaringInput) Generate(rnd *rand.Rand, size int) reflect.Value {
+	set := New[uint32]()
+	for i := rnd.Intn(size + 1); i > 0; i-- {
+		set.Add(rnd.Uint32() % (4 << 16))
+	}
+	if rnd.Intn(2) == 0 {
+		// dense block, stored as a bitmap
+		base := uint32(rnd.Intn(4)) << 16
+		for i := 0; i < 6000; i++ {
+			set.Add(base + uint32(rnd.Intn(1<<16)))
+		}
+	}
+	if rnd.Intn(2) == 0 {
+		// long run
+		start := uint32(rnd.Intn(4 << 16))
+		for v := start; v < start+uint32(rnd.Intn(10000)); v++ {
+			set.Add(v)
+		}
+	}
+	return reflect.ValueOf(roaringInput(set))
+}
+
+func newRoaringInput(in roaringInput, optimize bool) *RoaringSet {
+	r := NewRoaringFromSet(Set[uint32](in))
+	if optimize {
+		r.RunOptimize()
+	}
+	return r
+}
+
+func TestRoaringSetBasic(t *testing.T) {
+	var r RoaringSet
+	require.Equal(t, 0, r.Len())
+	require.False(t, r.Contains(1))
+	require.Equal(t, []uint32{}, r.ToSlice())
+
+	r.AddAll([]uint32{1 << 20, 5, 3, 5, 70000})
+	require.Equal(t, 4, r.Len())
+	require.Equal(t, []uint32{3, 5, 70000, 1 << 20}, r.ToSlice())
+	require.True(t, r.Contains(70000))
+	require.False(t, r.Contains(70001))
+
+	r.RemoveAll([]uint32{3, 70000, 42})
+	require.Equal(t, []uint32{5, 1 << 20}, r.ToSlice())
+	require.Len(t, r.keys, 2)
+
+	r.Remove(5)
+	r.Remove(1 << 20)
+	require.Empty(t, r.keys)
+	require.Equal(t, []uint32{5}, NewRoaringFromSlice([]uint32{5}).ToSlice())
+}
+
+func TestRoaringSetContainerKinds(t *testing.T) {
+	r := NewRoaring()
+	for v := uint32(0); v < roaringMaxArray; v++ {
+		r.Add(v * 2)
+	}
+	require.Equal(t, byte(roaringArray), r.containers[0].kind)
+
+	r.Add(1)
+	require.Equal(t, byte(roaringBitmap), r.containers[0].kind)
+	require.Equal(t, roaringMaxArray+1, r.Len())
+
+	r.Remove(1)
+	require.Equal(t, byte(roaringArray), r.containers[0].kind)
+
+	run := NewRoaring()
+	for v := uint32(100); v < 20000; v++ {
+		run.Add(v)
+	}
+	require.Equal(t, byte(roaringBitmap), run.containers[0].kind)
+	run.RunOptimize()
+	require.Equal(t, byte(roaringRun), run.containers[0].kind)
+	require.Equal(t, 19900, run.Len())
+	require.True(t, run.Contains(100))
+	require.True(t, run.Contains(19999))
+	require.False(t, run.Contains(99))
+	require.False(t, run.Contains(20000))
+
+	run.Add(50)
+	require.NotEqual(t, byte(roaringRun), run.containers[0].kind)
+	require.Equal(t, 19901, run.Len())
+	run.RunOptimize()
+	run.Remove(1000)
+	require.NotEqual(t, byte(roaringRun), run.containers[0].kind)
+	require.Equal(t, 19900, run.Len())
+
+	sparse := NewRoaringFromSlice([]uint32{1, 100, 1000})
+	sparse.RunOptimize()
+	require.Equal(t, byte(roaringArray), sparse.containers[0].kind)
+}
+
+func TestRoaringSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          []uint32
+		other        []uint32
+		union        []uint32
+		intersection []uint32
+		difference   []uint32
+		equals       bool
+		subset       bool
+		properSubset bool
+	}{
+		{
+			name:         "empty sets",
+			set:          []uint32{},
+			other:        []uint32{},
+			union:        []uint32{},
+			intersection: []uint32{},
+			difference:   []uint32{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "proper subset",
+			set:          []uint32{1, 1 << 17},
+			other:        []uint32{1, 2, 1 << 17},
+			union:        []uint32{1, 2, 1 << 17},
+			intersection: []uint32{1, 1 << 17},
+			difference:   []uint32{},
+			equals:       false,
+			subset:       true,
+			properSubset: true,
+		},
+		{
+			name:         "partial overlap across containers",
+			set:          []uint32{1, 2, 1 << 16, 1 << 20},
+			other:        []uint32{2, 1 << 16, 1 << 18},
+			union:        []uint32{1, 2, 1 << 16, 1 << 18, 1 << 20},
+			intersection: []uint32{2, 1 << 16},
+			difference:   []uint32{1, 1 << 20},
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := NewRoaringFromSlice(c.set)
+			other := NewRoaringFromSlice(c.other)
+			require.Equal(t, c.union, r.Union(other).ToSlice())
+			require.Equal(t, c.intersection, r.Intersection(other).ToSlice())
+			require.Equal(t, len(c.intersection), r.IntersectionLen(other))
+			require.Equal(t, c.difference, r.Difference(other).ToSlice())
+			require.Equal(t, c.equals, r.Equals(other))
+			require.Equal(t, c.subset, r.IsSubsetOf(other))
+			require.Equal(t, c.properSubset, r.IsProperSubsetOf(other))
+		})
+	}
+}
+
+func TestRoaringSetMatchesSet(t *testing.T) {
+	config := &quick.Config{MaxCount: 30, Rand: rand.New(rand.NewSource(1))}
+	for _, optimize := range []bool{false, true} {
+		property := func(a, b roaringInput) bool {
+			sa, sb := Set[uint32](a), Set[uint32](b)
+			ra, rb := newRoaringInput(a, optimize), newRoaringInput(b, !optimize)
+			return ra.Len() == len(sa) &&
+				ra.ToSet().Equals(sa) &&
+				slices.IsSorted(ra.ToSlice()) &&
+				ra.Union(rb).ToSet().Equals(sa.Union(sb)) &&
+				ra.Intersection(rb).ToSet().Equals(sa.Intersection(sb)) &&
+				ra.IntersectionLen(rb) == len(sa.Intersection(sb)) &&
+				ra.Difference(rb).ToSet().Equals(sa.Difference(sb)) &&
+				ra.Equals(rb) == sa.Equals(sb) &&
+				ra.Equals(newRoaringInput(a, !optimize)) &&
+				ra.IsSubsetOf(rb) == sa.IsSubsetOf(sb) &&
+				ra.IsSubsetOf(ra.Union(rb))
+		}
+		require.NoError(t, quick.Check(property, config))
+	}
+}
+
+func TestRoaringSetMutationsMatchSet(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	r := NewRoaring()
+	set := New[uint32]()
+	for i := 0; i < 50000; i++ {
+		v := uint32(rnd.Intn(3 << 16))
+		if rnd.Intn(4) == 0 {
+			r.Remove(v)
+			set.Remove(v)
+		} else {
+			r.Add(v)
+			set.Add(v)
+		}
+		if i%10000 == 0 {
+			r.RunOptimize()
+		}
+	}
+	require.Equal(t, set, r.ToSet())
+	for v := uint32(0); v < 3<<16; v += 7 {
+		require.Equal(t, set.Contains(v), r.Contains(v))
+	}
+}
+
+func TestRoaringSetBinaryRoundTrip(t *testing.T) {
+	config := &quick.Config{MaxCount: 20, Rand: rand.New(rand.NewSource(3))}
+	property := func(in roaringInput, optimize bool) bool {
+		r := newRoaringInput(in, optimize)
+		data, err := r.MarshalBinary()
+		if err != nil {
+			return false
+		}
+		var decoded RoaringSet
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			return false
+		}
+		again, _ := decoded.MarshalBinary()
+		return decoded.Equals(r) && slices.Equal(data, again)
+	}
+	require.NoError(t, quick.Check(property, config))
+}
+
+func TestRoaringSetUnmarshalBinaryErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: []byte{}},
+		{name: "bad version", data: []byte{2, 0}},
+		{name: "missing count", data: []byte{1}},
+		{name: "too many containers", data: []byte{1, 5, 0, 0, 1, 1}},
+		{name: "unknown kind", data: []byte{1, 1, 0, 0, 9, 0}},
+		{name: "empty array", data: []byte{1, 1, 0, 0, 1, 0}},
+		{name: "array out of order", data: []byte{1, 1, 0, 0, 1, 2, 2, 0, 1, 0}},
+		{name: "truncated array", data: []byte{1, 1, 0, 0, 1, 2, 1, 0}},
+		{name: "keys out of order", data: []byte{1, 2, 1, 0, 1, 1, 1, 0, 0, 0, 1, 1, 1, 0}},
+		{name: "truncated bitmap", data: []byte{1, 1, 0, 0, 2, 0xff}},
+		{name: "bad run", data: []byte{1, 1, 0, 0, 3, 1, 5, 0, 1, 0}},
+		{name: "adjacent runs", data: []byte{1, 1, 0, 0, 3, 2, 0, 0, 1, 0, 2, 0, 3, 0}},
+		{name: "trailing bytes", data: []byte{1, 0, 0}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var r RoaringSet
+			require.ErrorIs(t, r.UnmarshalBinary(c.data), ErrInvalidEncoding)
+		})
+	}
+
+	sparse := make([]byte, 0, 5+roaringWords*8)
+	sparse = append(sparse, 1, 1, 0, 0, 2)
+	sparse = append(sparse, make([]byte, roaringWords*8)...)
+	var r RoaringSet
+	require.ErrorIs(t, r.UnmarshalBinary(sparse), ErrInvalidEncoding)
+}
+
+func FuzzRoaringSetUnmarshalBinary(f *testing.F) {
+	dense := NewRoaring()
+	for v := uint32(0); v < 10000; v += 2 {
+		dense.Add(v)
+	}
+	run := NewRoaring()
+	for v := uint32(1 << 16); v < 1<<16+500; v++ {
+		run.Add(v)
+	}
+	run.RunOptimize()
+	for _, seed := range []*RoaringSet{NewRoaringFromSlice([]uint32{1, 2, 1 << 20}), dense, run} {
+		data, err := seed.MarshalBinary()
+		require.NoError(f, err)
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var r RoaringSet
+		if err := r.UnmarshalBinary(data); err != nil {
+			return
+		}
+		encoded, err := r.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, data, encoded)
+		require.Equal(t, r.Len(), len(r.ToSet()))
+	})
+}
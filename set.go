@@ -54,6 +54,11 @@ func NewFromMapKeys[T comparable, V any](m map[T]V) Set[T] {
 	return set
 }
 
+// Len returns the number of elements in a Set.
+func (set Set[T]) Len() int {
+	return len(set)
+}
+
 // ToSlice returns an unordered slice of elements from a Set.
 func (set Set[T]) ToSlice() []T {
 	slice := make([]T, 0, len(set))
@@ -63,6 +68,15 @@ func (set Set[T]) ToSlice() []T {
 	return slice
 }
 
+// clone returns a shallow copy of a Set.
+func (set Set[T]) clone() Set[T] {
+	result := make(Set[T], len(set))
+	for s := range set {
+		result[s] = struct{}{}
+	}
+	return result
+}
+
 // Equals returns true if two Sets are equal.
 func (set Set[T]) Equals(other Set[T]) bool {
 	if len(set) != len(other) {
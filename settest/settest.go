@@ -0,0 +1,372 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+// Package settest implements a conformance suite for implementations of
+// set.MutableSet, checking both the basic operations and the laws of set
+// algebra.
+package settest
+
+import (
+	"math/rand"
+	"testing"
+
+	set "github.com/felixenescu/golang-map-set"
+)
+
+// rounds is the number of random inputs each law is checked against.
+const rounds = 50
+
+// Factory describes the implementation S under test, holding elements of
+// type T.
+//
+// The algebra functions are those of the implementation, usually method
+// expressions like (*MySet[int]).Union. Left nil, they are replaced by
+// generic versions built on All, Contains and Add, so the laws only check
+// those. Results are compared element by element, never with Equals.
+type Factory[T any, S set.MutableSet[T]] struct {
+	// New returns a new, empty set.
+	New func() S
+	// Elem returns a distinct element for every non-negative integer.
+	Elem func(i int) T
+
+	Union        func(a, b S) S
+	Intersection func(a, b S) S
+	Difference   func(a, b S) S
+	Equals       func(a, b S) bool
+	IsSubsetOf   func(a, b S) bool
+}
+
+// RunConformance runs the conformance suite against the sets created by f,
+// as subtests of t.
+func RunConformance[T any, S set.MutableSet[T]](t *testing.T, f Factory[T, S]) {
+	a := algebra[T, S]{Factory: f}
+	if a.Union == nil {
+		a.Union = a.genericUnion
+	}
+	if a.Intersection == nil {
+		a.Intersection = a.genericIntersection
+	}
+	if a.Difference == nil {
+		a.Difference = a.genericDifference
+	}
+	if a.Equals == nil {
+		a.Equals = same[T, S]
+	}
+	if a.IsSubsetOf == nil {
+		a.IsSubsetOf = isSubset[T, S]
+	}
+
+	t.Run("Basic", a.testBasic)
+	t.Run("NoSideEffects", a.testNoSideEffects)
+	t.Run("Comparisons", a.testComparisons)
+	t.Run("Commutativity", a.testCommutativity)
+	t.Run("Associativity", a.testAssociativity)
+	t.Run("Distributivity", a.testDistributivity)
+	t.Run("DeMorgan", a.testDeMorgan)
+	t.Run("SubsetTransitivity", a.testSubsetTransitivity)
+	t.Run("Idempotence", a.testIdempotence)
+	t.Run("EmptySet", a.testEmptySet)
+}
+
+// algebra is a Factory with every algebra function set.
+type algebra[T any, S set.MutableSet[T]] struct {
+	Factory[T, S]
+}
+
+func (a algebra[T, S]) testBasic(t *testing.T) {
+	s := a.New()
+	if s.Len() != 0 {
+		t.Fatalf("new set has Len %d, want 0", s.Len())
+	}
+	if s.Contains(a.Elem(0)) {
+		t.Fatalf("new set contains %v", a.Elem(0))
+	}
+
+	// expected holds the indexes of the elements in s
+	expected := set.New[int]()
+	for i := 0; i < 100; i += 3 {
+		s.Add(a.Elem(i))
+		s.Add(a.Elem(i))
+		expected.Add(i)
+	}
+	for i := 0; i < 100; i += 4 {
+		s.Remove(a.Elem(i))
+		expected.Remove(i)
+	}
+	s.Remove(a.Elem(1000))
+	if s.Len() != len(expected) {
+		t.Fatalf("Len is %d, want %d", s.Len(), len(expected))
+	}
+	for i := 0; i < 100; i++ {
+		if s.Contains(a.Elem(i)) != expected.Contains(i) {
+			t.Fatalf("Contains(%v) is %v, want %v", a.Elem(i), s.Contains(a.Elem(i)), expected.Contains(i))
+		}
+	}
+
+	// adding what All yields to a new set reveals duplicates and strays
+	n := 0
+	seen := a.New()
+	for e := range s.All() {
+		if !s.Contains(e) {
+			t.Fatalf("All yields %v, which is not contained", e)
+		}
+		seen.Add(e)
+		n++
+	}
+	if n != len(expected) || seen.Len() != len(expected) {
+		t.Fatalf("All yields %d elements, %d distinct, want %d", n, seen.Len(), len(expected))
+	}
+
+	n = 0
+	for range s.All() {
+		n++
+		if n == 5 {
+			break
+		}
+	}
+	if n != 5 {
+		t.Fatalf("All stopped after %d elements, want 5", n)
+	}
+}
+
+func (a algebra[T, S]) testNoSideEffects(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < rounds; i++ {
+		x, y := a.randomSet(rnd), a.randomSet(rnd)
+		xc, yc := a.clone(x), a.clone(y)
+		a.Union(x, y)
+		a.Intersection(x, y)
+		a.Difference(x, y)
+		a.Equals(x, y)
+		a.IsSubsetOf(x, y)
+		if !same[T](x, xc) || !same[T](y, yc) {
+			t.Fatalf("operations modified their operands %v and %v", a.slice(xc), a.slice(yc))
+		}
+	}
+}
+
+func (a algebra[T, S]) testComparisons(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	for i := 0; i < rounds; i++ {
+		x, y := a.randomSet(rnd), a.randomSet(rnd)
+		if rnd.Intn(3) == 0 {
+			y = a.clone(x)
+		}
+		if a.Equals(x, y) != same[T](x, y) {
+			t.Fatalf("Equals is %v for %v and %v", a.Equals(x, y), a.slice(x), a.slice(y))
+		}
+		if a.IsSubsetOf(x, y) != isSubset[T](x, y) {
+			t.Fatalf("IsSubsetOf is %v for %v and %v", a.IsSubsetOf(x, y), a.slice(x), a.slice(y))
+		}
+		xy := a.Intersection(x, y)
+		if !a.IsSubsetOf(xy, x) || !a.IsSubsetOf(x, a.Union(x, y)) {
+			t.Fatalf("A∩B ⊆ A ⊆ A∪B does not hold for %v and %v", a.slice(x), a.slice(y))
+		}
+		if a.Union(x, y).Len() != x.Len()+y.Len()-xy.Len() {
+			t.Fatalf("|A∪B| == |A|+|B|-|A∩B| does not hold for %v and %v", a.slice(x), a.slice(y))
+		}
+	}
+}
+
+func (a algebra[T, S]) testCommutativity(t *testing.T) {
+	rnd := rand.New(rand.NewSource(3))
+	for i := 0; i < rounds; i++ {
+		x, y := a.randomSet(rnd), a.randomSet(rnd)
+		a.check(t, "A∪B == B∪A", a.Union(x, y), a.Union(y, x))
+		a.check(t, "A∩B == B∩A", a.Intersection(x, y), a.Intersection(y, x))
+		if a.Equals(x, y) != a.Equals(y, x) {
+			t.Fatalf("Equals is not symmetric for %v and %v", a.slice(x), a.slice(y))
+		}
+	}
+}
+
+func (a algebra[T, S]) testAssociativity(t *testing.T) {
+	rnd := rand.New(rand.NewSource(4))
+	for i := 0; i < rounds; i++ {
+		x, y, z := a.randomSet(rnd), a.randomSet(rnd), a.randomSet(rnd)
+		a.check(t, "(A∪B)∪C == A∪(B∪C)", a.Union(a.Union(x, y), z), a.Union(x, a.Union(y, z)))
+		a.check(t, "(A∩B)∩C == A∩(B∩C)",
+			a.Intersection(a.Intersection(x, y), z), a.Intersection(x, a.Intersection(y, z)))
+	}
+}
+
+func (a algebra[T, S]) testDistributivity(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	for i := 0; i < rounds; i++ {
+		x, y, z := a.randomSet(rnd), a.randomSet(rnd), a.randomSet(rnd)
+		a.check(t, "A∩(B∪C) == (A∩B)∪(A∩C)",
+			a.Intersection(x, a.Union(y, z)), a.Union(a.Intersection(x, y), a.Intersection(x, z)))
+		a.check(t, "A∪(B∩C) == (A∪B)∩(A∪C)",
+			a.Union(x, a.Intersection(y, z)), a.Intersection(a.Union(x, y), a.Union(x, z)))
+	}
+}
+
+func (a algebra[T, S]) testDeMorgan(t *testing.T) {
+	rnd := rand.New(rand.NewSource(6))
+	for i := 0; i < rounds; i++ {
+		u, x, y := a.randomSet(rnd), a.randomSet(rnd), a.randomSet(rnd)
+		notX, notY := a.Difference(u, x), a.Difference(u, y)
+		a.check(t, "U∖(A∪B) == (U∖A)∩(U∖B)", a.Difference(u, a.Union(x, y)), a.Intersection(notX, notY))
+		a.check(t, "U∖(A∩B) == (U∖A)∪(U∖B)", a.Difference(u, a.Intersection(x, y)), a.Union(notX, notY))
+	}
+}
+
+func (a algebra[T, S]) testSubsetTransitivity(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for i := 0; i < rounds; i++ {
+		x, y, z := a.randomSet(rnd), a.randomSet(rnd), a.randomSet(rnd)
+		xy := a.Intersection(x, y)
+		xyz := a.Intersection(xy, z)
+		if !a.IsSubsetOf(xyz, xy) || !a.IsSubsetOf(xy, x) {
+			t.Fatalf("intersection is not a subset of its operands")
+		}
+		if !a.IsSubsetOf(xyz, x) {
+			t.Fatalf("A∩B∩C ⊆ A∩B ⊆ A but not A∩B∩C ⊆ A for %v", a.slice(xyz))
+		}
+		if a.IsSubsetOf(x, y) && a.IsSubsetOf(y, z) && !a.IsSubsetOf(x, z) {
+			t.Fatalf("A ⊆ B ⊆ C but not A ⊆ C for %v, %v, %v", a.slice(x), a.slice(y), a.slice(z))
+		}
+		if (a.IsSubsetOf(x, y) && a.IsSubsetOf(y, x)) != a.Equals(x, y) {
+			t.Fatalf("A ⊆ B and B ⊆ A disagrees with Equals for %v, %v", a.slice(x), a.slice(y))
+		}
+	}
+}
+
+func (a algebra[T, S]) testIdempotence(t *testing.T) {
+	rnd := rand.New(rand.NewSource(8))
+	for i := 0; i < rounds; i++ {
+		x := a.randomSet(rnd)
+		a.check(t, "A∪A == A", a.Union(x, x), x)
+		a.check(t, "A∩A == A", a.Intersection(x, x), x)
+		if !a.Equals(x, x) || !a.IsSubsetOf(x, x) {
+			t.Fatalf("A is not equal to and a subset of itself for %v", a.slice(x))
+		}
+
+		before := x.Len()
+		for _, e := range a.slice(x) {
+			x.Add(e)
+		}
+		if x.Len() != before {
+			t.Fatalf("adding present elements changed Len from %d to %d", before, x.Len())
+		}
+	}
+}
+
+func (a algebra[T, S]) testEmptySet(t *testing.T) {
+	rnd := rand.New(rand.NewSource(9))
+	for i := 0; i < rounds; i++ {
+		x, empty := a.randomSet(rnd), a.New()
+		a.check(t, "A∪∅ == A", a.Union(x, empty), x)
+		a.check(t, "∅∪A == A", a.Union(empty, x), x)
+		a.check(t, "A∩∅ == ∅", a.Intersection(x, empty), empty)
+		a.check(t, "A∖∅ == A", a.Difference(x, empty), x)
+		a.check(t, "∅∖A == ∅", a.Difference(empty, x), empty)
+		a.check(t, "A∖A == ∅", a.Difference(x, x), empty)
+		if !a.IsSubsetOf(empty, x) {
+			t.Fatalf("∅ is not a subset of %v", a.slice(x))
+		}
+		if x.Len() > 0 && (a.IsSubsetOf(x, empty) || a.Equals(x, empty)) {
+			t.Fatalf("%v is a subset of ∅", a.slice(x))
+		}
+	}
+}
+
+// randomSet returns a set of up to 20 elements drawn from the first 40
+// elements of the Factory, so that random sets overlap often.
+func (a algebra[T, S]) randomSet(rnd *rand.Rand) S {
+	s := a.New()
+	for n := rnd.Intn(21); n > 0; n-- {
+		s.Add(a.Elem(rnd.Intn(40)))
+	}
+	return s
+}
+
+func (a algebra[T, S]) clone(s S) S {
+	result := a.New()
+	for e := range s.All() {
+		result.Add(e)
+	}
+	return result
+}
+
+func (a algebra[T, S]) genericUnion(x, y S) S {
+	result := a.clone(x)
+	for e := range y.All() {
+		result.Add(e)
+	}
+	return result
+}
+
+func (a algebra[T, S]) genericIntersection(x, y S) S {
+	result := a.New()
+	for e := range x.All() {
+		if y.Contains(e) {
+			result.Add(e)
+		}
+	}
+	return result
+}
+
+func (a algebra[T, S]) genericDifference(x, y S) S {
+	result := a.New()
+	for e := range x.All() {
+		if !y.Contains(e) {
+			result.Add(e)
+		}
+	}
+	return result
+}
+
+func (a algebra[T, S]) slice(s S) []T {
+	slice := make([]T, 0, s.Len())
+	for e := range s.All() {
+		slice = append(slice, e)
+	}
+	return slice
+}
+
+func (a algebra[T, S]) check(t *testing.T, law string, actual, expected S) {
+	t.Helper()
+	if !same[T](actual, expected) {
+		t.Fatalf("%s does not hold: got %v, want %v", law, a.slice(actual), a.slice(expected))
+	}
+}
+
+// isSubset reports whether every element of x is in y, using only All and
+// Contains.
+func isSubset[T any, S set.MutableSet[T]](x, y S) bool {
+	for e := range x.All() {
+		if !y.Contains(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// same reports whether x and y hold the same elements, using only Len, All
+// and Contains.
+func same[T any, S set.MutableSet[T]](x, y S) bool {
+	return x.Len() == y.Len() && isSubset[T](x, y)
+}
@@ -0,0 +1,178 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package settest_test
+
+import (
+	"strconv"
+	"testing"
+
+	set "github.com/felixenescu/golang-map-set"
+	"github.com/felixenescu/golang-map-set/settest"
+)
+
+func TestConformance(t *testing.T) {
+	itself := func(i int) int { return i }
+	t.Run("Set", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, set.Set[int]]{
+			New:          set.New[int],
+			Elem:         itself,
+			Union:        set.Set[int].Union,
+			Intersection: set.Set[int].Intersection,
+			Difference:   set.Set[int].Difference,
+			Equals:       set.Set[int].Equals,
+			IsSubsetOf:   set.Set[int].IsSubsetOf,
+		})
+	})
+	t.Run("SetOfStrings", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[string, set.Set[string]]{
+			New:          set.New[string],
+			Elem:         strconv.Itoa,
+			Union:        set.Set[string].Union,
+			Intersection: set.Set[string].Intersection,
+			Difference:   set.Set[string].Difference,
+			Equals:       set.Set[string].Equals,
+			IsSubsetOf:   set.Set[string].IsSubsetOf,
+		})
+	})
+	t.Run("ConcurrentSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, *set.ConcurrentSet[int]]{
+			New:          set.NewConcurrent[int],
+			Elem:         itself,
+			Union:        (*set.ConcurrentSet[int]).Union,
+			Intersection: (*set.ConcurrentSet[int]).Intersection,
+			Difference:   (*set.ConcurrentSet[int]).Difference,
+			Equals:       (*set.ConcurrentSet[int]).Equals,
+			IsSubsetOf:   (*set.ConcurrentSet[int]).IsSubsetOf,
+		})
+	})
+	t.Run("ShardedSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, *set.ShardedSet[int]]{
+			New:          func() *set.ShardedSet[int] { return set.NewSharded[int](4) },
+			Elem:         itself,
+			Union:        (*set.ShardedSet[int]).Union,
+			Intersection: (*set.ShardedSet[int]).Intersection,
+			Difference:   (*set.ShardedSet[int]).Difference,
+			Equals:       (*set.ShardedSet[int]).Equals,
+			IsSubsetOf:   (*set.ShardedSet[int]).IsSubsetOf,
+		})
+	})
+	t.Run("SortedSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, *set.SortedSet[int]]{
+			New:          set.NewSorted[int],
+			Elem:         itself,
+			Union:        (*set.SortedSet[int]).Union,
+			Intersection: (*set.SortedSet[int]).Intersection,
+			Difference:   (*set.SortedSet[int]).Difference,
+			Equals:       (*set.SortedSet[int]).Equals,
+			IsSubsetOf:   (*set.SortedSet[int]).IsSubsetOf,
+		})
+	})
+	t.Run("OrderedSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, *set.OrderedSet[int]]{
+			New:          set.NewOrdered[int],
+			Elem:         itself,
+			Union:        (*set.OrderedSet[int]).Union,
+			Intersection: (*set.OrderedSet[int]).Intersection,
+			Difference:   (*set.OrderedSet[int]).Difference,
+			Equals:       (*set.OrderedSet[int]).Equals,
+			IsSubsetOf:   (*set.OrderedSet[int]).IsSubsetOf,
+		})
+	})
+	t.Run("HashSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[string, *set.HashSet[string]]{
+			New:          func() *set.HashSet[string] { return set.NewHashSet(set.NewFoldHasher()) },
+			Elem:         strconv.Itoa,
+			Union:        (*set.HashSet[string]).Union,
+			Intersection: (*set.HashSet[string]).Intersection,
+			Difference:   (*set.HashSet[string]).Difference,
+			Equals:       (*set.HashSet[string]).Equals,
+			IsSubsetOf:   (*set.HashSet[string]).IsSubsetOf,
+		})
+	})
+	t.Run("HashSetOfBytes", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[[]byte, *set.HashSet[[]byte]]{
+			New:          func() *set.HashSet[[]byte] { return set.NewHashSet(set.NewBytesHasher()) },
+			Elem:         func(i int) []byte { return []byte(strconv.Itoa(i)) },
+			Union:        (*set.HashSet[[]byte]).Union,
+			Intersection: (*set.HashSet[[]byte]).Intersection,
+			Difference:   (*set.HashSet[[]byte]).Difference,
+			Equals:       (*set.HashSet[[]byte]).Equals,
+			IsSubsetOf:   (*set.HashSet[[]byte]).IsSubsetOf,
+		})
+	})
+	t.Run("KeyedSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, *set.KeyedSet[string, int]]{
+			New:          func() *set.KeyedSet[string, int] { return set.NewKeyed(strconv.Itoa) },
+			Elem:         itself,
+			Union:        (*set.KeyedSet[string, int]).Union,
+			Intersection: (*set.KeyedSet[string, int]).Intersection,
+			Difference:   (*set.KeyedSet[string, int]).Difference,
+			Equals:       (*set.KeyedSet[string, int]).Equals,
+			IsSubsetOf:   (*set.KeyedSet[string, int]).IsSubsetOf,
+		})
+	})
+	// the bounded sets have no algebra of their own
+	t.Run("LRUSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, *set.LRUSet[int]]{
+			New:  func() *set.LRUSet[int] { return set.NewLRU[int](1000) },
+			Elem: itself,
+		})
+	})
+	t.Run("LFUSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, *set.LFUSet[int]]{
+			New:  func() *set.LFUSet[int] { return set.NewLFU[int](1000) },
+			Elem: itself,
+		})
+	})
+	t.Run("FIFOSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[int, *set.FIFOSet[int]]{
+			New:  func() *set.FIFOSet[int] { return set.NewFIFO[int](1000) },
+			Elem: itself,
+		})
+	})
+	t.Run("BitSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[uint, *set.BitSet]{
+			New:          set.NewBitSet,
+			Elem:         func(i int) uint { return uint(i) },
+			Union:        (*set.BitSet).Union,
+			Intersection: (*set.BitSet).Intersection,
+			Difference:   (*set.BitSet).Difference,
+			Equals:       (*set.BitSet).Equals,
+			IsSubsetOf:   (*set.BitSet).IsSubsetOf,
+		})
+	})
+	t.Run("RoaringSet", func(t *testing.T) {
+		settest.RunConformance(t, settest.Factory[uint32, *set.RoaringSet]{
+			New:          set.NewRoaring,
+			Elem:         func(i int) uint32 { return uint32(i) * 1000 },
+			Union:        (*set.RoaringSet).Union,
+			Intersection: (*set.RoaringSet).Intersection,
+			Difference:   (*set.RoaringSet).Difference,
+			Equals:       (*set.RoaringSet).Equals,
+			IsSubsetOf:   (*set.RoaringSet).IsSubsetOf,
+		})
+	})
+}
@@ -0,0 +1,249 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"hash/maphash"
+	"iter"
+)
+
+// DefaultShards is the number of shards used by a ShardedSet when a non
+// positive shard count is requested.
+const DefaultShards = 32
+
+// ShardedSet is a generic, threadsafe set data structure for high contention
+// workloads. Elements are hashed across a fixed number of independently locked
+// Set shards, so writers to different shards do not block each other.
+// Operations that span all shards (Len, ToSlice, Snapshot, Union, ...) lock
+// every shard, so they observe a consistent state.
+type ShardedSet[T comparable] struct {
+	shards []ConcurrentSet[T]
+	hash   func(T) uint64
+}
+
+// NewSharded creates a new ShardedSet with the given number of shards.
+// Elements are hashed with a default hasher, which hashes equal elements
+// alike, walking structs, arrays and interfaces and hashing pointers by
+// address. Use NewShardedWithHasher for a faster hash of composite types.
+func NewSharded[T comparable](shards int) *ShardedSet[T] {
+	return NewShardedWithHasher(shards, defaultHasher[T]())
+}
+
+// NewShardedFromSlice creates a new ShardedSet with the given number of shards
+// from a slice of comparable.
+func NewShardedFromSlice[T comparable](shards int, slice []T) *ShardedSet[T] {
+	ss := NewSharded[T](shards)
+	ss.AddAll(slice)
+	return ss
+}
+
+// NewShardedWithHasher creates a new ShardedSet with the given number of
+// shards and a custom hash function. Equal elements must have equal hashes.
+func NewShardedWithHasher[T comparable](shards int, hash func(T) uint64) *ShardedSet[T] {
+	if shards < 1 {
+		shards = DefaultShards
+	}
+	ss := &ShardedSet[T]{
+		shards: make([]ConcurrentSet[T], shards),
+		hash:   hash,
+	}
+	for i := range ss.shards {
+		ss.shards[i].set = New[T]()
+	}
+	return ss
+}
+
+// ShardCount returns the number of shards of a ShardedSet.
+func (ss *ShardedSet[T]) ShardCount() int {
+	return len(ss.shards)
+}
+
+func (ss *ShardedSet[T]) shardFor(s T) *ConcurrentSet[T] {
+	return &ss.shards[ss.hash(s)%uint64(len(ss.shards))]
+}
+
+// rlockAll read locks all shards, always in the same order.
+func (ss *ShardedSet[T]) rlockAll() {
+	for i := range ss.shards {
+		ss.shards[i].mu.RLock()
+	}
+}
+
+func (ss *ShardedSet[T]) runlockAll() {
+	for i := range ss.shards {
+		ss.shards[i].mu.RUnlock()
+	}
+}
+
+// Len returns the number of elements in a ShardedSet.
+func (ss *ShardedSet[T]) Len() int {
+	ss.rlockAll()
+	defer ss.runlockAll()
+	n := 0
+	for i := range ss.shards {
+		n += len(ss.shards[i].set)
+	}
+	return n
+}
+
+// Snapshot returns a consistent copy of the ShardedSet content as a plain Set.
+func (ss *ShardedSet[T]) Snapshot() Set[T] {
+	ss.rlockAll()
+	defer ss.runlockAll()
+	n := 0
+	for i := range ss.shards {
+		n += len(ss.shards[i].set)
+	}
+	result := make(Set[T], n)
+	for i := range ss.shards {
+		for s := range ss.shards[i].set {
+			result[s] = struct{}{}
+		}
+	}
+	return result
+}
+
+// ToSlice returns an unordered slice of elements from a ShardedSet.
+func (ss *ShardedSet[T]) ToSlice() []T {
+	ss.rlockAll()
+	defer ss.runlockAll()
+	slice := make([]T, 0)
+	for i := range ss.shards {
+		for s := range ss.shards[i].set {
+			slice = append(slice, s)
+		}
+	}
+	return slice
+}
+
+// All returns an iterator over a consistent snapshot of the elements of a
+// ShardedSet, in no particular order. Changes made while iterating are not
+// observed.
+func (ss *ShardedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for s := range ss.Snapshot() {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// Contains returns true if a ShardedSet contains an element.
+func (ss *ShardedSet[T]) Contains(s T) bool {
+	return ss.shardFor(s).Contains(s)
+}
+
+// Add adds an element to a ShardedSet.
+func (ss *ShardedSet[T]) Add(s T) {
+	ss.shardFor(s).Add(s)
+}
+
+// Remove removes an element from a ShardedSet.
+func (ss *ShardedSet[T]) Remove(s T) {
+	ss.shardFor(s).Remove(s)
+}
+
+// AddAll adds a slice of elements to a ShardedSet. The elements are not added
+// atomically, concurrent readers may observe only part of them.
+func (ss *ShardedSet[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		ss.Add(s)
+	}
+}
+
+// RemoveAll removes a slice of elements from a ShardedSet. The elements are
+// not removed atomically, concurrent readers may observe only part of them.
+func (ss *ShardedSet[T]) RemoveAll(slice []T) {
+	for _, s := range slice {
+		ss.Remove(s)
+	}
+}
+
+// AddIfAbsent adds an element to a ShardedSet and returns true if it was not
+// already present. The check and the insertion happen atomically.
+func (ss *ShardedSet[T]) AddIfAbsent(s T) bool {
+	return ss.shardFor(s).AddIfAbsent(s)
+}
+
+// RemoveIfPresent removes an element from a ShardedSet and returns true if it
+// was present. The check and the removal happen atomically.
+func (ss *ShardedSet[T]) RemoveIfPresent(s T) bool {
+	return ss.shardFor(s).RemoveIfPresent(s)
+}
+
+// Equals returns true if two ShardedSets are equal.
+func (ss *ShardedSet[T]) Equals(other *ShardedSet[T]) bool {
+	return ss.Snapshot().Equals(other.Snapshot())
+}
+
+// IsSubsetOf returns true if a ShardedSet is a subset of another ShardedSet
+// (they can be equal).
+func (ss *ShardedSet[T]) IsSubsetOf(other *ShardedSet[T]) bool {
+	return ss.Snapshot().IsSubsetOf(other.Snapshot())
+}
+
+// Union returns the union of two ShardedSets as new ShardedSet with the same
+// shard count and hasher as the receiver.
+func (ss *ShardedSet[T]) Union(other *ShardedSet[T]) *ShardedSet[T] {
+	return ss.fromSet(ss.Snapshot().Union(other.Snapshot()))
+}
+
+// Intersection returns the intersection of two ShardedSets as new ShardedSet
+// with the same shard count and hasher as the receiver.
+func (ss *ShardedSet[T]) Intersection(other *ShardedSet[T]) *ShardedSet[T] {
+	return ss.fromSet(ss.Snapshot().Intersection(other.Snapshot()))
+}
+
+// Difference returns the difference of two ShardedSets as new ShardedSet with
+// the same shard count and hasher as the receiver.
+func (ss *ShardedSet[T]) Difference(other *ShardedSet[T]) *ShardedSet[T] {
+	return ss.fromSet(ss.Snapshot().Difference(other.Snapshot()))
+}
+
+// fromSet creates a new ShardedSet shaped like ss holding the elements of set.
+func (ss *ShardedSet[T]) fromSet(set Set[T]) *ShardedSet[T] {
+	result := NewShardedWithHasher(len(ss.shards), ss.hash)
+	for s := range set {
+		result.shardFor(s).set[s] = struct{}{}
+	}
+	return result
+}
+
+// defaultHasher returns a seeded hash function for any comparable type.
+func defaultHasher[T comparable]() func(T) uint64 {
+	seed := maphash.MakeSeed()
+	return func(s T) uint64 {
+		return hashAny(seed, s)
+	}
+}
+
+// hashAny hashes the canonical encoding of v, so that it agrees with
+// stableHash on which values are equal.
+func hashAny(seed maphash.Seed, v any) uint64 {
+	var buf [16]byte
+	return maphash.Bytes(seed, appendStable(buf[:0], v))
+}
@@ -0,0 +1,179 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardedSetBasic(t *testing.T) {
+	ss := NewShardedFromSlice(4, []string{"a", "b", "c"})
+	require.Equal(t, 4, ss.ShardCount())
+	require.Equal(t, 3, ss.Len())
+	require.True(t, ss.Contains("a"))
+	require.False(t, ss.Contains("d"))
+
+	ss.Add("d")
+	ss.Remove("a")
+	ss.AddAll([]string{"e", "f"})
+	ss.RemoveAll([]string{"b", "x"})
+	require.Equal(t, Set[string]{"c": struct{}{}, "d": struct{}{}, "e": struct{}{}, "f": struct{}{}}, ss.Snapshot())
+
+	actual := ss.ToSlice()
+	slices.Sort(actual)
+	require.Equal(t, []string{"c", "d", "e", "f"}, actual)
+
+	require.True(t, ss.AddIfAbsent("g"))
+	require.False(t, ss.AddIfAbsent("g"))
+	require.True(t, ss.RemoveIfPresent("g"))
+	require.False(t, ss.RemoveIfPresent("g"))
+}
+
+func TestShardedSetDefaultShards(t *testing.T) {
+	require.Equal(t, DefaultShards, NewSharded[int](0).ShardCount())
+	require.Equal(t, DefaultShards, NewSharded[int](-1).ShardCount())
+}
+
+func TestShardedSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          []int
+		other        []int
+		union        Set[int]
+		intersection Set[int]
+		difference   Set[int]
+		equals       bool
+		subset       bool
+	}{
+		{
+			name:         "empty sets",
+			set:          []int{},
+			other:        []int{},
+			union:        Set[int]{},
+			intersection: Set[int]{},
+			difference:   Set[int]{},
+			equals:       true,
+			subset:       true,
+		},
+		{
+			name:         "equal sets",
+			set:          []int{1, 2, 3},
+			other:        []int{1, 2, 3},
+			union:        Set[int]{1: struct{}{}, 2: struct{}{}, 3: struct{}{}},
+			intersection: Set[int]{1: struct{}{}, 2: struct{}{}, 3: struct{}{}},
+			difference:   Set[int]{},
+			equals:       true,
+			subset:       true,
+		},
+		{
+			name:         "partial overlap",
+			set:          []int{1, 2, 3},
+			other:        []int{2, 3, 4},
+			union:        Set[int]{1: struct{}{}, 2: struct{}{}, 3: struct{}{}, 4: struct{}{}},
+			intersection: Set[int]{2: struct{}{}, 3: struct{}{}},
+			difference:   Set[int]{1: struct{}{}},
+			equals:       false,
+			subset:       false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ss := NewShardedFromSlice(3, c.set)
+			other := NewShardedFromSlice(5, c.other)
+			union := ss.Union(other)
+			require.Equal(t, c.union, union.Snapshot())
+			require.Equal(t, ss.ShardCount(), union.ShardCount())
+			require.Equal(t, c.intersection, ss.Intersection(other).Snapshot())
+			require.Equal(t, c.difference, ss.Difference(other).Snapshot())
+			require.Equal(t, c.equals, ss.Equals(other))
+			require.Equal(t, c.subset, ss.IsSubsetOf(other))
+		})
+	}
+}
+
+func TestShardedSetDefaultHasher(t *testing.T) {
+	type point struct{ X, Y int }
+	a, b := &point{1, 2}, &point{1, 2}
+
+	ss := NewSharded[any](8)
+	ss.AddAll([]any{"a", 1, int64(1), 2.5, true, point{1, 2}, a, nil})
+	require.True(t, ss.Contains("a"))
+	require.True(t, ss.Contains(1))
+	require.True(t, ss.Contains(int64(1)))
+	require.True(t, ss.Contains(2.5))
+	require.True(t, ss.Contains(true))
+	require.True(t, ss.Contains(point{1, 2}))
+	require.True(t, ss.Contains(a))
+	require.True(t, ss.Contains(nil))
+	require.False(t, ss.Contains(b))
+
+	// mutating the pointed value must not move the pointer to another shard
+	a.X = 10
+	require.True(t, ss.Contains(a))
+
+	negZero := NewSharded[float64](8)
+	negZero.Add(0)
+	zero := 0.0
+	require.True(t, negZero.Contains(-zero))
+}
+
+func TestShardedSetWithHasher(t *testing.T) {
+	ss := NewShardedWithHasher(4, func(s int) uint64 { return uint64(s) })
+	ss.AddAll([]int{0, 1, 2, 3, 4, 5, 6, 7})
+	for i := range ss.shards {
+		require.Len(t, ss.shards[i].set, 2)
+	}
+}
+
+func TestShardedSetStress(t *testing.T) {
+	const workers = 16
+	ss := NewSharded[int](8)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				ss.Add(w*1000 + i)
+				ss.Contains(i)
+				if i%2 == 0 {
+					ss.Remove(w*1000 + i)
+				}
+				if i%100 == 0 {
+					ss.Len()
+					ss.ToSlice()
+					ss.Union(ss)
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	require.Equal(t, workers*500, ss.Len())
+}
@@ -0,0 +1,150 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultMinHashPermutations is the number of permutations used by
+// NewMinHash when the requested one is not positive. The standard error of
+// estimates is at most 0.5 / sqrt(permutations).
+const DefaultMinHashPermutations = 128
+
+// Jaccard returns the Jaccard index of two Sets, the size of their
+// intersection divided by the size of their union. Two empty Sets have an
+// index of 1.
+func (set Set[T]) Jaccard(other Set[T]) float64 {
+	if len(set) == 0 && len(other) == 0 {
+		return 1
+	}
+	n := set.intersectionLen(other)
+	return float64(n) / float64(len(set)+len(other)-n)
+}
+
+// Dice returns the Sørensen–Dice coefficient of two Sets, twice the size of
+// their intersection divided by the sum of their sizes. Two empty Sets have
+// a coefficient of 1.
+func (set Set[T]) Dice(other Set[T]) float64 {
+	if len(set) == 0 && len(other) == 0 {
+		return 1
+	}
+	return 2 * float64(set.intersectionLen(other)) / float64(len(set)+len(other))
+}
+
+// Overlap returns the overlap coefficient of two Sets, the size of their
+// intersection divided by the size of the smaller one. It is 1 when one is a
+// subset of the other, except that an empty Set has a coefficient of 0 with
+// a non-empty one.
+func (set Set[T]) Overlap(other Set[T]) float64 {
+	if len(set) == 0 && len(other) == 0 {
+		return 1
+	}
+	if len(set) == 0 || len(other) == 0 {
+		return 0
+	}
+	return float64(set.intersectionLen(other)) / float64(min(len(set), len(other)))
+}
+
+// intersectionLen returns the size of the intersection of two Sets without
+// building it.
+func (set Set[T]) intersectionLen(other Set[T]) int {
+	if len(set) > len(other) {
+		set, other = other, set
+	}
+	n := 0
+	for s := range set {
+		if _, ok := other[s]; ok {
+			n++
+		}
+	}
+	return n
+}
+
+// MinHash computes signatures of sets for estimating their Jaccard index
+// without comparing their elements. Signatures are stable across processes
+// and can be compared if computed with the same number of permutations.
+type MinHash[T comparable] struct {
+	seeds []uint64
+}
+
+// Signature is a MinHash signature of a set: the smallest hash of its
+// elements under every permutation.
+type Signature []uint64
+
+// NewMinHash creates a new MinHash using a number of permutations, each one
+// adding a value to signatures.
+func NewMinHash[T comparable](permutations int) *MinHash[T] {
+	if permutations < 1 {
+		permutations = DefaultMinHashPermutations
+	}
+	seeds := make([]uint64, permutations)
+	seed := uint64(0)
+	for i := range seeds {
+		// splitmix64 sequence
+		seed += 0x9e3779b97f4a7c15
+		seeds[i] = mix64(seed)
+	}
+	return &MinHash[T]{seeds: seeds}
+}
+
+// Permutations returns the number of permutations of a MinHash.
+func (mh *MinHash[T]) Permutations() int {
+	return len(mh.seeds)
+}
+
+// Signature returns the signature of a set. Every empty set has the same
+// signature.
+func (mh *MinHash[T]) Signature(set ReadableSet[T]) Signature {
+	sig := make(Signature, len(mh.seeds))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for s := range set.All() {
+		h, _ := stableHash(s)
+		for i, seed := range mh.seeds {
+			sig[i] = min(sig[i], mix64(h^seed))
+		}
+	}
+	return sig
+}
+
+// Similarity returns the estimated Jaccard index of the sets of two
+// Signatures, the fraction of permutations under which their minimums agree.
+// It returns ErrIncompatible if their lengths differ.
+func (sig Signature) Similarity(other Signature) (float64, error) {
+	if len(sig) != len(other) || len(sig) == 0 {
+		return 0, fmt.Errorf("%w: signatures of %d and %d permutations", ErrIncompatible, len(sig), len(other))
+	}
+	equal := 0
+	for i, v := range sig {
+		if v == other[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(sig)), nil
+}
@@ -0,0 +1,112 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetSimilarity(t *testing.T) {
+	cases := []struct {
+		name    string
+		set     []string
+		other   []string
+		jaccard float64
+		dice    float64
+		overlap float64
+	}{
+		{name: "empty sets", set: []string{}, other: []string{}, jaccard: 1, dice: 1, overlap: 1},
+		{name: "one empty set", set: []string{}, other: []string{"a"}, jaccard: 0, dice: 0, overlap: 0},
+		{name: "equal sets", set: []string{"a", "b"}, other: []string{"b", "a"}, jaccard: 1, dice: 1, overlap: 1},
+		{name: "disjoint sets", set: []string{"a", "b"}, other: []string{"c"}, jaccard: 0, dice: 0, overlap: 0},
+		{name: "subset", set: []string{"a"}, other: []string{"a", "b", "c", "d"}, jaccard: 0.25, dice: 0.4, overlap: 1},
+		{name: "partial overlap", set: []string{"a", "b", "c"}, other: []string{"b", "c", "d", "e", "f"}, jaccard: 2.0 / 6, dice: 0.5, overlap: 2.0 / 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			set, other := NewFromSlice(c.set), NewFromSlice(c.other)
+			require.InDelta(t, c.jaccard, set.Jaccard(other), 1e-12)
+			require.InDelta(t, c.jaccard, other.Jaccard(set), 1e-12)
+			require.InDelta(t, c.dice, set.Dice(other), 1e-12)
+			require.InDelta(t, c.overlap, set.Overlap(other), 1e-12)
+			require.InDelta(t, c.overlap, other.Overlap(set), 1e-12)
+
+			if len(set) > 0 || len(other) > 0 {
+				exact := float64(len(set.Intersection(other))) / float64(len(set.Union(other)))
+				require.InDelta(t, exact, set.Jaccard(other), 1e-12)
+			}
+		})
+	}
+}
+
+func TestMinHashPermutations(t *testing.T) {
+	require.Equal(t, 64, NewMinHash[int](64).Permutations())
+	require.Equal(t, DefaultMinHashPermutations, NewMinHash[int](0).Permutations())
+	require.Len(t, NewMinHash[int](16).Signature(New[int]()), 16)
+}
+
+func TestMinHashSimilarity(t *testing.T) {
+	const permutations = 256
+	mh := NewMinHash[int](permutations)
+	for _, shared := range []int{0, 100, 250, 500, 900, 1000} {
+		t.Run(fmt.Sprint(shared), func(t *testing.T) {
+			// two sets of 1000 elements sharing some of them
+			a, b := New[int](), New[int]()
+			for i := 0; i < 1000; i++ {
+				a.Add(i)
+				b.Add(i + 1000 - shared)
+			}
+			exact := float64(len(a.Intersection(b))) / float64(len(a.Union(b)))
+			estimate, err := mh.Signature(a).Similarity(mh.Signature(b))
+			require.NoError(t, err)
+			stdErr := math.Sqrt(exact * (1 - exact) / permutations)
+			require.InDelta(t, exact, estimate, 4*stdErr+0.01, "exact %v, estimate %v", exact, estimate)
+		})
+	}
+}
+
+func TestMinHashSignature(t *testing.T) {
+	mh := NewMinHash[string](32)
+	a := NewFromSlice([]string{"go", "sets", "hash"})
+
+	// signatures only depend on the elements and the permutations
+	require.Equal(t, mh.Signature(a), NewMinHash[string](32).Signature(a.Freeze()))
+	require.Equal(t, mh.Signature(a), mh.Signature(NewSortedFromSlice([]string{"hash", "sets", "go"})))
+	require.Equal(t, mh.Signature(New[string]()), mh.Signature(New[string]()))
+
+	similarity, err := mh.Signature(a).Similarity(mh.Signature(a))
+	require.NoError(t, err)
+	require.Equal(t, 1.0, similarity)
+
+	_, err = mh.Signature(a).Similarity(NewMinHash[string](16).Signature(a))
+	require.ErrorIs(t, err, ErrIncompatible)
+	_, err = Signature{}.Similarity(Signature{})
+	require.ErrorIs(t, err, ErrIncompatible)
+}
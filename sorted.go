@@ -0,0 +1,502 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import "cmp"
+
+// SortedSet is a generic, not threadsafe set data structure keeping its
+// elements ordered. It is backed by an AVL tree augmented with subtree sizes,
+// so Add, Remove, Contains, Floor, Ceiling, Rank and Select are O(log n).
+// Set operations between two SortedSets use a linear merge of their elements.
+type SortedSet[T any] struct {
+	root *sortedNode[T]
+	cmp  func(a, b T) int
+}
+
+type sortedNode[T any] struct {
+	item        T
+	left, right *sortedNode[T]
+	height      int
+	size        int
+}
+
+// NewSorted creates a new SortedSet of ordered elements.
+func NewSorted[T cmp.Ordered]() *SortedSet[T] {
+	return &SortedSet[T]{cmp: cmp.Compare[T]}
+}
+
+// NewSortedFunc creates a new SortedSet ordered by a comparison function,
+// which must return a negative number when a < b, a positive number when
+// a > b and zero when a and b are equal.
+func NewSortedFunc[T any](cmp func(a, b T) int) *SortedSet[T] {
+	return &SortedSet[T]{cmp: cmp}
+}
+
+// NewSortedFromSlice creates a new SortedSet from a slice of ordered elements.
+func NewSortedFromSlice[T cmp.Ordered](slice []T) *SortedSet[T] {
+	set := NewSorted[T]()
+	set.AddAll(slice)
+	return set
+}
+
+// NewSortedFromSet creates a new SortedSet from the elements of a Set.
+func NewSortedFromSet[T cmp.Ordered](set Set[T]) *SortedSet[T] {
+	sorted := NewSorted[T]()
+	for s := range set {
+		sorted.Add(s)
+	}
+	return sorted
+}
+
+// Len returns the number of elements in a SortedSet.
+func (set *SortedSet[T]) Len() int {
+	return set.root.sizeOf()
+}
+
+// ToSlice returns an ordered slice of elements from a SortedSet.
+func (set *SortedSet[T]) ToSlice() []T {
+	slice := make([]T, 0, set.Len())
+	set.Ascend(func(s T) bool {
+		slice = append(slice, s)
+		return true
+	})
+	return slice
+}
+
+// Ascend calls fn for every element of a SortedSet in ascending order, until
+// fn returns false.
+func (set *SortedSet[T]) Ascend(fn func(T) bool) {
+	set.root.ascend(fn)
+}
+
+// Descend calls fn for every element of a SortedSet in descending order, until
+// fn returns false.
+func (set *SortedSet[T]) Descend(fn func(T) bool) {
+	set.root.descend(fn)
+}
+
+// Contains returns true if a SortedSet contains an element.
+func (set *SortedSet[T]) Contains(s T) bool {
+	n := set.root
+	for n != nil {
+		c := set.cmp(s, n.item)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// Add adds an element to a SortedSet.
+func (set *SortedSet[T]) Add(s T) {
+	set.root, _ = set.insert(set.root, s)
+}
+
+// Remove removes an element from a SortedSet.
+func (set *SortedSet[T]) Remove(s T) {
+	set.root, _ = set.delete(set.root, s)
+}
+
+// AddAll adds a slice of elements to a SortedSet.
+func (set *SortedSet[T]) AddAll(slice []T) {
+	for _, s := range slice {
+		set.Add(s)
+	}
+}
+
+// RemoveAll removes a slice of elements from a SortedSet.
+func (set *SortedSet[T]) RemoveAll(slice []T) {
+	for _, s := range slice {
+		set.Remove(s)
+	}
+}
+
+// Min returns the smallest element of a SortedSet, or false if it is empty.
+func (set *SortedSet[T]) Min() (T, bool) {
+	var zero T
+	n := set.root
+	if n == nil {
+		return zero, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.item, true
+}
+
+// Max returns the largest element of a SortedSet, or false if it is empty.
+func (set *SortedSet[T]) Max() (T, bool) {
+	var zero T
+	n := set.root
+	if n == nil {
+		return zero, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.item, true
+}
+
+// Floor returns the largest element less than or equal to s, or false if
+// there is none.
+func (set *SortedSet[T]) Floor(s T) (T, bool) {
+	var result T
+	found := false
+	n := set.root
+	for n != nil {
+		c := set.cmp(s, n.item)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			result, found = n.item, true
+			n = n.right
+		default:
+			return n.item, true
+		}
+	}
+	return result, found
+}
+
+// Ceiling returns the smallest element greater than or equal to s, or false
+// if there is none.
+func (set *SortedSet[T]) Ceiling(s T) (T, bool) {
+	var result T
+	found := false
+	n := set.root
+	for n != nil {
+		c := set.cmp(s, n.item)
+		switch {
+		case c < 0:
+			result, found = n.item, true
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.item, true
+		}
+	}
+	return result, found
+}
+
+// Range returns an ordered slice of the elements between lo and hi, both
+// inclusive.
+func (set *SortedSet[T]) Range(lo, hi T) []T {
+	slice := make([]T, 0)
+	set.root.ascendRange(lo, hi, set.cmp, func(s T) bool {
+		slice = append(slice, s)
+		return true
+	})
+	return slice
+}
+
+// Rank returns the number of elements of a SortedSet strictly less than s.
+func (set *SortedSet[T]) Rank(s T) int {
+	rank := 0
+	n := set.root
+	for n != nil {
+		c := set.cmp(s, n.item)
+		switch {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			rank += n.left.sizeOf() + 1
+			n = n.right
+		default:
+			return rank + n.left.sizeOf()
+		}
+	}
+	return rank
+}
+
+// Select returns the k-th smallest element of a SortedSet, counting from 0,
+// or false if k is out of range.
+func (set *SortedSet[T]) Select(k int) (T, bool) {
+	var zero T
+	if k < 0 || k >= set.Len() {
+		return zero, false
+	}
+	n := set.root
+	for {
+		l := n.left.sizeOf()
+		switch {
+		case k < l:
+			n = n.left
+		case k > l:
+			k -= l + 1
+			n = n.right
+		default:
+			return n.item, true
+		}
+	}
+}
+
+// Equals returns true if two SortedSets are equal.
+func (set *SortedSet[T]) Equals(other *SortedSet[T]) bool {
+	if set.Len() != other.Len() {
+		return false
+	}
+	a, b := set.ToSlice(), other.ToSlice()
+	for i := range a {
+		if set.cmp(a[i], b[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSubsetOf returns true if a SortedSet is a subset of another SortedSet
+// (they can be equal).
+func (set *SortedSet[T]) IsSubsetOf(other *SortedSet[T]) bool {
+	if set.Len() > other.Len() {
+		return false
+	}
+	return len(set.merge(other, false, true, false)) == set.Len()
+}
+
+// IsProperSubsetOf returns true if a SortedSet is a proper subset of another
+// SortedSet (they cannot be equal).
+func (set *SortedSet[T]) IsProperSubsetOf(other *SortedSet[T]) bool {
+	return set.Len() < other.Len() && set.IsSubsetOf(other)
+}
+
+// Union returns the union of two SortedSets as new SortedSet. Both sets must
+// use the same ordering, the result uses the receiver's one.
+func (set *SortedSet[T]) Union(other *SortedSet[T]) *SortedSet[T] {
+	return set.fromSorted(set.merge(other, true, true, true))
+}
+
+// Intersection returns the intersection of two SortedSets as new SortedSet.
+// Both sets must use the same ordering, the result uses the receiver's one.
+func (set *SortedSet[T]) Intersection(other *SortedSet[T]) *SortedSet[T] {
+	return set.fromSorted(set.merge(other, false, true, false))
+}
+
+// Difference returns the difference of two SortedSets as new SortedSet. Both
+// sets must use the same ordering, the result uses the receiver's one.
+func (set *SortedSet[T]) Difference(other *SortedSet[T]) *SortedSet[T] {
+	return set.fromSorted(set.merge(other, true, false, false))
+}
+
+// merge walks both sets in order and keeps the elements found only in set,
+// in both sets and only in other, as selected.
+func (set *SortedSet[T]) merge(other *SortedSet[T], onlySet, both, onlyOther bool) []T {
+	a, b := set.ToSlice(), other.ToSlice()
+	result := make([]T, 0)
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		c := set.cmp(a[i], b[j])
+		switch {
+		case c < 0:
+			if onlySet {
+				result = append(result, a[i])
+			}
+			i++
+		case c > 0:
+			if onlyOther {
+				result = append(result, b[j])
+			}
+			j++
+		default:
+			if both {
+				result = append(result, a[i])
+			}
+			i++
+			j++
+		}
+	}
+	if onlySet {
+		result = append(result, a[i:]...)
+	}
+	if onlyOther {
+		result = append(result, b[j:]...)
+	}
+	return result
+}
+
+// fromSorted builds a perfectly balanced SortedSet from an ordered slice
+// without duplicates in O(n).
+func (set *SortedSet[T]) fromSorted(slice []T) *SortedSet[T] {
+	return &SortedSet[T]{root: buildSorted(slice), cmp: set.cmp}
+}
+
+func buildSorted[T any](slice []T) *sortedNode[T] {
+	if len(slice) == 0 {
+		return nil
+	}
+	mid := len(slice) / 2
+	n := &sortedNode[T]{
+		item:  slice[mid],
+		left:  buildSorted(slice[:mid]),
+		right: buildSorted(slice[mid+1:]),
+	}
+	n.update()
+	return n
+}
+
+func (set *SortedSet[T]) insert(n *sortedNode[T], s T) (*sortedNode[T], bool) {
+	if n == nil {
+		return &sortedNode[T]{item: s, height: 1, size: 1}, true
+	}
+	var added bool
+	c := set.cmp(s, n.item)
+	switch {
+	case c < 0:
+		n.left, added = set.insert(n.left, s)
+	case c > 0:
+		n.right, added = set.insert(n.right, s)
+	default:
+		return n, false
+	}
+	if !added {
+		return n, false
+	}
+	return n.rebalance(), true
+}
+
+func (set *SortedSet[T]) delete(n *sortedNode[T], s T) (*sortedNode[T], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var removed bool
+	c := set.cmp(s, n.item)
+	switch {
+	case c < 0:
+		n.left, removed = set.delete(n.left, s)
+	case c > 0:
+		n.right, removed = set.delete(n.right, s)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// replace with the in-order successor
+		succ := n.right
+		for succ.left != nil {
+			succ = succ.left
+		}
+		n.item = succ.item
+		n.right, _ = set.delete(n.right, succ.item)
+		removed = true
+	}
+	if !removed {
+		return n, false
+	}
+	return n.rebalance(), true
+}
+
+func (n *sortedNode[T]) sizeOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *sortedNode[T]) heightOf() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *sortedNode[T]) update() {
+	n.height = 1 + max(n.left.heightOf(), n.right.heightOf())
+	n.size = 1 + n.left.sizeOf() + n.right.sizeOf()
+}
+
+func (n *sortedNode[T]) rotateLeft() *sortedNode[T] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *sortedNode[T]) rotateRight() *sortedNode[T] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func (n *sortedNode[T]) rebalance() *sortedNode[T] {
+	n.update()
+	balance := n.left.heightOf() - n.right.heightOf()
+	switch {
+	case balance > 1:
+		if n.left.left.heightOf() < n.left.right.heightOf() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case balance < -1:
+		if n.right.right.heightOf() < n.right.left.heightOf() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+func (n *sortedNode[T]) ascend(fn func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.ascend(fn) && fn(n.item) && n.right.ascend(fn)
+}
+
+func (n *sortedNode[T]) descend(fn func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.right.descend(fn) && fn(n.item) && n.left.descend(fn)
+}
+
+func (n *sortedNode[T]) ascendRange(lo, hi T, cmp func(a, b T) int, fn func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	cLo, cHi := cmp(n.item, lo), cmp(n.item, hi)
+	if cLo > 0 && !n.left.ascendRange(lo, hi, cmp, fn) {
+		return false
+	}
+	if cLo >= 0 && cHi <= 0 && !fn(n.item) {
+		return false
+	}
+	if cHi < 0 {
+		return n.right.ascendRange(lo, hi, cmp, fn)
+	}
+	return true
+}
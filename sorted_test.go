@@ -0,0 +1,265 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// checkSorted verifies the AVL and size invariants of a subtree and returns
+// its height.
+func checkSorted[T any](t *testing.T, n *sortedNode[T]) int {
+	if n == nil {
+		return 0
+	}
+	l, r := checkSorted(t, n.left), checkSorted(t, n.right)
+	require.LessOrEqual(t, l-r, 1)
+	require.GreaterOrEqual(t, l-r, -1)
+	require.Equal(t, 1+max(l, r), n.height)
+	require.Equal(t, 1+n.left.sizeOf()+n.right.sizeOf(), n.size)
+	return n.height
+}
+
+func TestSortedSetBasic(t *testing.T) {
+	set := NewSortedFromSlice([]int{5, 3, 8, 1, 3})
+	require.Equal(t, 4, set.Len())
+	require.Equal(t, []int{1, 3, 5, 8}, set.ToSlice())
+	require.True(t, set.Contains(5))
+	require.False(t, set.Contains(4))
+
+	set.Remove(3)
+	set.Remove(42)
+	set.AddAll([]int{2, 9})
+	set.RemoveAll([]int{1, 9})
+	require.Equal(t, []int{2, 5, 8}, set.ToSlice())
+
+	descending := make([]int, 0)
+	set.Descend(func(s int) bool {
+		descending = append(descending, s)
+		return true
+	})
+	require.Equal(t, []int{8, 5, 2}, descending)
+
+	first := make([]int, 0)
+	set.Ascend(func(s int) bool {
+		first = append(first, s)
+		return len(first) < 2
+	})
+	require.Equal(t, []int{2, 5}, first)
+}
+
+func TestSortedSetEmpty(t *testing.T) {
+	set := NewSorted[string]()
+	require.Equal(t, 0, set.Len())
+	require.Equal(t, []string{}, set.ToSlice())
+	_, ok := set.Min()
+	require.False(t, ok)
+	_, ok = set.Max()
+	require.False(t, ok)
+	_, ok = set.Floor("a")
+	require.False(t, ok)
+	_, ok = set.Ceiling("a")
+	require.False(t, ok)
+	_, ok = set.Select(0)
+	require.False(t, ok)
+	require.Equal(t, 0, set.Rank("a"))
+	require.Equal(t, []string{}, set.Range("a", "z"))
+}
+
+func TestSortedSetQueries(t *testing.T) {
+	set := NewSortedFromSlice([]int{10, 20, 30, 40, 50})
+
+	first, _ := set.Min()
+	require.Equal(t, 10, first)
+	last, _ := set.Max()
+	require.Equal(t, 50, last)
+
+	cases := []struct {
+		name        string
+		s           int
+		floor       int
+		floorOK     bool
+		ceiling     int
+		ceilingOK   bool
+		rank        int
+		rangeToNext []int
+	}{
+		{name: "below min", s: 5, floor: 0, floorOK: false, ceiling: 10, ceilingOK: true, rank: 0, rangeToNext: []int{10}},
+		{name: "member", s: 30, floor: 30, floorOK: true, ceiling: 30, ceilingOK: true, rank: 2, rangeToNext: []int{30, 40}},
+		{name: "between", s: 35, floor: 30, floorOK: true, ceiling: 40, ceilingOK: true, rank: 3, rangeToNext: []int{40}},
+		{name: "above max", s: 55, floor: 50, floorOK: true, ceiling: 0, ceilingOK: false, rank: 5, rangeToNext: []int{}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			floor, ok := set.Floor(c.s)
+			require.Equal(t, c.floorOK, ok)
+			require.Equal(t, c.floor, floor)
+			ceiling, ok := set.Ceiling(c.s)
+			require.Equal(t, c.ceilingOK, ok)
+			require.Equal(t, c.ceiling, ceiling)
+			require.Equal(t, c.rank, set.Rank(c.s))
+			require.Equal(t, c.rangeToNext, set.Range(c.s, c.s+10))
+		})
+	}
+
+	for k := 0; k < set.Len(); k++ {
+		s, ok := set.Select(k)
+		require.True(t, ok)
+		require.Equal(t, (k+1)*10, s)
+		require.Equal(t, k, set.Rank(s))
+	}
+	_, ok := set.Select(-1)
+	require.False(t, ok)
+	_, ok = set.Select(5)
+	require.False(t, ok)
+	require.Equal(t, []int{}, set.Range(40, 20))
+}
+
+func TestSortedSetFunc(t *testing.T) {
+	set := NewSortedFunc(func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	set.AddAll([]string{"b", "A", "a", "C"})
+	require.Equal(t, []string{"A", "b", "C"}, set.ToSlice())
+	require.True(t, set.Contains("c"))
+}
+
+func TestSortedSetOperations(t *testing.T) {
+	cases := []struct {
+		name         string
+		set          []string
+		other        []string
+		union        []string
+		intersection []string
+		difference   []string
+		equals       bool
+		subset       bool
+		properSubset bool
+	}{
+		{
+			name:         "empty sets",
+			set:          []string{},
+			other:        []string{},
+			union:        []string{},
+			intersection: []string{},
+			difference:   []string{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "equal sets",
+			set:          []string{"a", "b", "c"},
+			other:        []string{"c", "b", "a"},
+			union:        []string{"a", "b", "c"},
+			intersection: []string{"a", "b", "c"},
+			difference:   []string{},
+			equals:       true,
+			subset:       true,
+			properSubset: false,
+		},
+		{
+			name:         "proper subset",
+			set:          []string{"b"},
+			other:        []string{"a", "b", "c"},
+			union:        []string{"a", "b", "c"},
+			intersection: []string{"b"},
+			difference:   []string{},
+			equals:       false,
+			subset:       true,
+			properSubset: true,
+		},
+		{
+			name:         "partial overlap",
+			set:          []string{"a", "b", "c"},
+			other:        []string{"b", "c", "d"},
+			union:        []string{"a", "b", "c", "d"},
+			intersection: []string{"b", "c"},
+			difference:   []string{"a"},
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+		{
+			name:         "same size different sets",
+			set:          []string{"a", "b"},
+			other:        []string{"a", "c"},
+			union:        []string{"a", "b", "c"},
+			intersection: []string{"a"},
+			difference:   []string{"b"},
+			equals:       false,
+			subset:       false,
+			properSubset: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			set := NewSortedFromSlice(c.set)
+			other := NewSortedFromSlice(c.other)
+			require.Equal(t, c.union, set.Union(other).ToSlice())
+			require.Equal(t, c.intersection, set.Intersection(other).ToSlice())
+			require.Equal(t, c.difference, set.Difference(other).ToSlice())
+			require.Equal(t, c.equals, set.Equals(other))
+			require.Equal(t, c.subset, set.IsSubsetOf(other))
+			require.Equal(t, c.properSubset, set.IsProperSubsetOf(other))
+		})
+	}
+}
+
+func TestSortedSetMatchesSet(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	sorted := NewSorted[int]()
+	plain := New[int]()
+	for i := 0; i < 5000; i++ {
+		v := rnd.Intn(500)
+		if rnd.Intn(3) == 0 {
+			sorted.Remove(v)
+			plain.Remove(v)
+		} else {
+			sorted.Add(v)
+			plain.Add(v)
+		}
+	}
+	checkSorted(t, sorted.root)
+
+	expected := plain.ToSlice()
+	slices.Sort(expected)
+	require.Equal(t, expected, sorted.ToSlice())
+	require.Equal(t, sorted.ToSlice(), NewSortedFromSet(plain).ToSlice())
+
+	other := NewSortedFromSlice(randomInts(100))
+	union := sorted.Union(other)
+	checkSorted(t, union.root)
+	for _, s := range union.ToSlice() {
+		require.True(t, sorted.Contains(s) || other.Contains(s))
+	}
+	require.True(t, sorted.IsSubsetOf(union))
+}
@@ -0,0 +1,297 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SQLFormat selects how a Set is stored in a database column.
+type SQLFormat int
+
+const (
+	// SQLPostgresArray stores a Set as a PostgreSQL array literal, like {a,b,c}.
+	SQLPostgresArray SQLFormat = iota
+	// SQLJSONArray stores a Set as JSON array text, like ["a","b","c"].
+	SQLJSONArray
+)
+
+// SQLColumn adapts a Set to database/sql, implementing sql.Scanner and
+// driver.Valuer with the selected format. Create one per column with
+// PostgresArray or JSONArray, and pass it to Scan or as a query argument.
+type SQLColumn[T comparable] struct {
+	Set    *Set[T]
+	Format SQLFormat
+}
+
+// PostgresArray returns an SQLColumn storing a Set as a PostgreSQL array
+// literal. Elements must have a string, integer, float or bool underlying
+// type.
+func PostgresArray[T comparable](set *Set[T]) SQLColumn[T] {
+	return SQLColumn[T]{Set: set, Format: SQLPostgresArray}
+}
+
+// JSONArray returns an SQLColumn storing a Set as JSON array text.
+func JSONArray[T comparable](set *Set[T]) SQLColumn[T] {
+	return SQLColumn[T]{Set: set, Format: SQLJSONArray}
+}
+
+// Value implements driver.Valuer. Elements are sorted when their type is
+// ordered, so equal Sets are stored identically. A nil Set is stored as NULL.
+func (c SQLColumn[T]) Value() (driver.Value, error) {
+	if c.Set == nil || *c.Set == nil {
+		return nil, nil
+	}
+	switch c.Format {
+	case SQLPostgresArray:
+		return formatPostgresArray(*c.Set)
+	case SQLJSONArray:
+		b, err := c.Set.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		return string(b), nil
+	default:
+		return nil, fmt.Errorf("set: unknown SQL format %d", c.Format)
+	}
+}
+
+// Scan implements sql.Scanner. It replaces the content of the Set with the
+// elements stored in the column, a NULL column gives a nil Set.
+func (c SQLColumn[T]) Scan(src any) error {
+	if c.Set == nil {
+		return errors.New("set: Scan into nil *Set")
+	}
+	var text string
+	switch src := src.(type) {
+	case nil:
+		*c.Set = nil
+		return nil
+	case string:
+		text = src
+	case []byte:
+		text = string(src)
+	default:
+		return fmt.Errorf("set: cannot scan %T into Set", src)
+	}
+	switch c.Format {
+	case SQLPostgresArray:
+		return parsePostgresArray(c.Set, text)
+	case SQLJSONArray:
+		return unmarshalJSON(c.Set, []byte(text), false)
+	default:
+		return fmt.Errorf("set: unknown SQL format %d", c.Format)
+	}
+}
+
+func formatPostgresArray[T comparable](set Set[T]) (string, error) {
+	slice := set.ToSlice()
+	sortOrdered(slice)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, s := range slice {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		text, err := formatPostgresElement(reflect.ValueOf(s))
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(text)
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+func formatPostgresElement(v reflect.Value) (string, error) {
+	switch v.Kind() {
+	case reflect.String:
+		return quotePostgresElement(v.String()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	default:
+		return "", fmt.Errorf("set: cannot store %s in a PostgreSQL array", v.Type())
+	}
+}
+
+// quotePostgresElement quotes an array element when it would otherwise be
+// misread: empty, NULL, or holding delimiters, quotes or white space.
+func quotePostgresElement(s string) string {
+	if s != "" && !strings.EqualFold(s, "NULL") && !strings.ContainsAny(s, "{}\",\\ \t\n\r\v\f") {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+func parsePostgresArray[T comparable](set *Set[T], text string) error {
+	text = strings.TrimSpace(text)
+	if len(text) < 2 || text[0] != '{' || text[len(text)-1] != '}' {
+		return fmt.Errorf("set: invalid PostgreSQL array %q", text)
+	}
+	body := text[1 : len(text)-1]
+	result := New[T]()
+	if strings.TrimSpace(body) == "" {
+		*set = result
+		return nil
+	}
+	for i := 0; ; {
+		elem, quoted, next, err := nextPostgresElement(body, i)
+		if err != nil {
+			return fmt.Errorf("set: invalid PostgreSQL array %q: %w", text, err)
+		}
+		if !quoted && strings.EqualFold(elem, "NULL") {
+			return fmt.Errorf("set: NULL element in PostgreSQL array %q", text)
+		}
+		var s T
+		if err := parsePostgresElement(reflect.ValueOf(&s).Elem(), elem); err != nil {
+			return err
+		}
+		result[s] = struct{}{}
+		if next == len(body) {
+			break
+		}
+		i = next + 1 // skip the comma
+	}
+	*set = result
+	return nil
+}
+
+// nextPostgresElement reads one element of an array body starting at i, and
+// returns it with the index of the following comma or the end of body.
+func nextPostgresElement(body string, i int) (elem string, quoted bool, next int, err error) {
+	for i < len(body) && isPostgresSpace(body[i]) {
+		i++
+	}
+	if i < len(body) && body[i] == '"' {
+		var b strings.Builder
+		i++
+		for {
+			if i >= len(body) {
+				return "", false, 0, errors.New("unterminated quoted element")
+			}
+			c := body[i]
+			if c == '"' {
+				i++
+				break
+			}
+			if c == '\\' {
+				i++
+				if i >= len(body) {
+					return "", false, 0, errors.New("unterminated escape")
+				}
+				c = body[i]
+			}
+			b.WriteByte(c)
+			i++
+		}
+		for i < len(body) && isPostgresSpace(body[i]) {
+			i++
+		}
+		if i < len(body) && body[i] != ',' {
+			return "", false, 0, fmt.Errorf("unexpected %q after quoted element", body[i])
+		}
+		return b.String(), true, i, nil
+	}
+	start := i
+	for i < len(body) && body[i] != ',' {
+		switch body[i] {
+		case '{', '}', '"', '\\':
+			return "", false, 0, fmt.Errorf("unexpected %q in element", body[i])
+		}
+		i++
+	}
+	elem = strings.TrimRight(body[start:i], " \t\n\r\v\f")
+	if elem == "" {
+		return "", false, 0, errors.New("empty element")
+	}
+	return elem, false, i, nil
+}
+
+func isPostgresSpace(c byte) bool {
+	switch c {
+	case ' ', '\t', '\n', '\r', '\v', '\f':
+		return true
+	}
+	return false
+}
+
+func parsePostgresElement(v reflect.Value, text string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("set: %w", err)
+		}
+		v.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("set: %w", err)
+		}
+		v.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("set: %w", err)
+		}
+		v.SetFloat(x)
+	case reflect.Bool:
+		// PostgreSQL outputs booleans as t and f
+		switch strings.ToLower(text) {
+		case "t", "true":
+			v.SetBool(true)
+		case "f", "false":
+			v.SetBool(false)
+		default:
+			return fmt.Errorf("set: invalid boolean %q", text)
+		}
+	default:
+		return fmt.Errorf("set: cannot read %s from a PostgreSQL array", v.Type())
+	}
+	return nil
+}
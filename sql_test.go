@@ -0,0 +1,250 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ sql.Scanner   = SQLColumn[int]{}
+	_ driver.Valuer = SQLColumn[int]{}
+)
+
+// fakeDriver is a database/sql driver keeping a single column value in
+// memory. Every Exec stores its first argument, every Query returns it.
+type fakeDriver struct {
+	mu    sync.Mutex
+	value driver.Value
+}
+
+type fakeConn struct{ d *fakeDriver }
+type fakeStmt struct{ d *fakeDriver }
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.value = args[0]
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{value: s.d.value}, nil
+}
+
+func (r *fakeRows) Columns() []string { return []string{"tags"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+// Connect and Driver implement driver.Connector, so each test can open its
+// own fakeDriver with sql.OpenDB without registering it.
+func (d *fakeDriver) Connect(context.Context) (driver.Conn, error) { return fakeConn{d}, nil }
+func (d *fakeDriver) Driver() driver.Driver                        { return d }
+
+func openFakeDB(t *testing.T) (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{}
+	db := sql.OpenDB(d)
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestSQLColumnValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    driver.Valuer
+		expected driver.Value
+	}{
+		{name: "nil set", value: PostgresArray(new(Set[string])), expected: nil},
+		{name: "empty pg", value: PostgresArray(&Set[string]{}), expected: "{}"},
+		{name: "pg strings", value: PostgresArray(ptr(NewFromSlice([]string{"b", "a", "c"}))), expected: "{a,b,c}"},
+		{
+			name:     "pg quoting",
+			value:    PostgresArray(ptr(NewFromSlice([]string{"", "a b", `q"x`, `s\t`, "null", "{}", "c,d"}))),
+			expected: `{"","a b","c,d","null","q\"x","s\\t","{}"}`,
+		},
+		{name: "pg ints", value: PostgresArray(ptr(NewFromSlice([]int{10, -1, 2}))), expected: "{-1,2,10}"},
+		{name: "pg floats", value: PostgresArray(ptr(NewFromSlice([]float64{1.5, -2}))), expected: "{-2,1.5}"},
+		{name: "pg bools", value: PostgresArray(ptr(NewFromSlice([]bool{true}))), expected: "{true}"},
+		{name: "json strings", value: JSONArray(ptr(NewFromSlice([]string{"b", "a"}))), expected: `["a","b"]`},
+		{name: "json ints", value: JSONArray(ptr(NewFromSlice([]int{2, 1}))), expected: `[1,2]`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual, err := c.value.Value()
+			require.NoError(t, err)
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestSQLColumnValueErrors(t *testing.T) {
+	_, err := PostgresArray(ptr(NewFromSlice([]jsonPoint{{}}))).Value()
+	require.Error(t, err)
+	_, err = SQLColumn[int]{Set: ptr(New[int]()), Format: 42}.Value()
+	require.Error(t, err)
+}
+
+func TestSQLColumnScanPostgresArray(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      any
+		expected Set[string]
+		err      bool
+	}{
+		{name: "null", src: nil, expected: nil},
+		{name: "empty", src: "{}", expected: Set[string]{}},
+		{name: "bytes", src: []byte("{a,b}"), expected: NewFromSlice([]string{"a", "b"})},
+		{name: "spaces", src: " { a , b c ,d } ", expected: NewFromSlice([]string{"a", "b c", "d"})},
+		{name: "quoted", src: `{"a,b"," x ","q\"y","s\\t",""}`, expected: NewFromSlice([]string{"a,b", " x ", `q"y`, `s\t`, ""})},
+		{name: "quoted null", src: `{"NULL"}`, expected: NewFromSlice([]string{"NULL"})},
+		{name: "duplicates", src: "{a,a}", expected: NewFromSlice([]string{"a"})},
+		{name: "null element", src: "{a,NULL}", err: true},
+		{name: "missing braces", src: "a,b", err: true},
+		{name: "nested", src: "{{a}}", err: true},
+		{name: "empty element", src: "{a,,b}", err: true},
+		{name: "trailing comma", src: "{a,}", err: true},
+		{name: "unterminated quote", src: `{"a}`, err: true},
+		{name: "unterminated escape", src: `{"a\}`, err: true},
+		{name: "junk after quote", src: `{"a"b}`, err: true},
+		{name: "wrong type", src: 42, err: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			actual := Set[string]{"old": struct{}{}}
+			err := PostgresArray(&actual).Scan(c.src)
+			if c.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.expected, actual)
+		})
+	}
+}
+
+func TestSQLColumnScanTypes(t *testing.T) {
+	var ints Set[int16]
+	require.NoError(t, PostgresArray(&ints).Scan("{3,-1,2}"))
+	require.Equal(t, NewFromSlice([]int16{3, -1, 2}), ints)
+	require.Error(t, PostgresArray(&ints).Scan("{40000}"))
+
+	var uints Set[uint]
+	require.NoError(t, PostgresArray(&uints).Scan("{1,2}"))
+	require.Equal(t, NewFromSlice([]uint{1, 2}), uints)
+	require.Error(t, PostgresArray(&uints).Scan("{-1}"))
+
+	var floats Set[float32]
+	require.NoError(t, PostgresArray(&floats).Scan("{1.5,-2}"))
+	require.Equal(t, NewFromSlice([]float32{1.5, -2}), floats)
+	require.Error(t, PostgresArray(&floats).Scan("{x}"))
+
+	var bools Set[bool]
+	require.NoError(t, PostgresArray(&bools).Scan("{t,false}"))
+	require.Equal(t, NewFromSlice([]bool{true, false}), bools)
+	require.Error(t, PostgresArray(&bools).Scan("{maybe}"))
+
+	var structs Set[jsonPoint]
+	require.Error(t, PostgresArray(&structs).Scan("{a}"))
+
+	require.Error(t, PostgresArray[int](nil).Scan("{}"))
+	require.Error(t, SQLColumn[int16]{Set: &ints, Format: 42}.Scan("{}"))
+}
+
+func TestSQLColumnScanJSONArray(t *testing.T) {
+	var set Set[string]
+	require.NoError(t, JSONArray(&set).Scan(`["a","b"]`))
+	require.Equal(t, NewFromSlice([]string{"a", "b"}), set)
+	require.Error(t, JSONArray(&set).Scan(`{a,b}`))
+}
+
+func TestSQLColumnRoundTrip(t *testing.T) {
+	cases := []struct {
+		name   string
+		column func(*Set[string]) SQLColumn[string]
+	}{
+		{name: "postgres array", column: PostgresArray[string]},
+		{name: "json array", column: JSONArray[string]},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			db, _ := openFakeDB(t)
+			tags := NewFromSlice([]string{"go", "sql", "a b", `"quoted"`, "{braces}", ""})
+			_, err := db.Exec("INSERT INTO docs (tags) VALUES (?)", c.column(&tags))
+			require.NoError(t, err)
+
+			var actual Set[string]
+			require.NoError(t, db.QueryRow("SELECT tags FROM docs").Scan(c.column(&actual)))
+			require.Equal(t, tags, actual)
+		})
+	}
+}
+
+func TestSQLColumnNull(t *testing.T) {
+	db, d := openFakeDB(t)
+	var empty Set[int]
+	_, err := db.Exec("INSERT INTO docs (ids) VALUES (?)", PostgresArray(&empty))
+	require.NoError(t, err)
+	require.Nil(t, d.value)
+
+	actual := NewFromSlice([]int{1})
+	require.NoError(t, db.QueryRow("SELECT ids FROM docs").Scan(PostgresArray(&actual)))
+	require.Nil(t, actual)
+}
@@ -0,0 +1,231 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"sync"
+	"time"
+)
+
+// TTLSet is a generic, threadsafe set data structure whose elements expire
+// after a time to live. Expired elements are never reported as present; they
+// are evicted lazily when encountered, by EvictExpired, or periodically once
+// StartEviction was called.
+type TTLSet[T comparable] struct {
+	mu      sync.Mutex
+	expires map[T]time.Time
+	ttl     time.Duration
+	now     func() time.Time
+	onEvict func(T)
+	stop    chan struct{}
+}
+
+// NewTTL creates a new TTLSet whose elements expire after ttl by default. A
+// ttl of zero or less means elements never expire by default.
+func NewTTL[T comparable](ttl time.Duration) *TTLSet[T] {
+	return NewTTLWithClock[T](ttl, time.Now)
+}
+
+// NewTTLWithClock creates a new TTLSet reading the current time from now,
+// which lets tests control expiry.
+func NewTTLWithClock[T comparable](ttl time.Duration, now func() time.Time) *TTLSet[T] {
+	return &TTLSet[T]{expires: make(map[T]time.Time), ttl: ttl, now: now}
+}
+
+// OnEvict registers a callback called with every element evicted because it
+// expired. It is not called for elements removed with Remove. The callback
+// runs without the lock held, so it may use the TTLSet.
+func (ts *TTLSet[T]) OnEvict(fn func(T)) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.onEvict = fn
+}
+
+// Add adds an element to a TTLSet with the default TTL. Adding an element
+// already present resets its expiry.
+func (ts *TTLSet[T]) Add(s T) {
+	ts.AddWithTTL(s, ts.ttl)
+}
+
+// AddWithTTL adds an element to a TTLSet expiring after ttl. A ttl of zero or
+// less means the element never expires.
+func (ts *TTLSet[T]) AddWithTTL(s T, ttl time.Duration) {
+	ts.mu.Lock()
+	now := ts.now()
+	evicted := ts.evictIfExpired(s, now)
+	var at time.Time
+	if ttl > 0 {
+		at = now.Add(ttl)
+	}
+	ts.expires[s] = at
+	fn := ts.onEvict
+	ts.mu.Unlock()
+	if evicted && fn != nil {
+		fn(s)
+	}
+}
+
+// Remove removes an element from a TTLSet.
+func (ts *TTLSet[T]) Remove(s T) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	delete(ts.expires, s)
+}
+
+// Contains returns true if a TTLSet contains an element that has not
+// expired.
+func (ts *TTLSet[T]) Contains(s T) bool {
+	ts.mu.Lock()
+	_, ok := ts.expires[s]
+	evicted := ts.evictIfExpired(s, ts.now())
+	fn := ts.onEvict
+	ts.mu.Unlock()
+	if evicted && fn != nil {
+		fn(s)
+	}
+	return ok && !evicted
+}
+
+// ExpiresAt returns when an element expires, the zero time if it never does,
+// or false if it is not present or has expired.
+func (ts *TTLSet[T]) ExpiresAt(s T) (time.Time, bool) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	at, ok := ts.expires[s]
+	if !ok || expired(at, ts.now()) {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// Len returns the number of elements of a TTLSet that have not expired.
+func (ts *TTLSet[T]) Len() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	now := ts.now()
+	n := 0
+	for _, at := range ts.expires {
+		if !expired(at, now) {
+			n++
+		}
+	}
+	return n
+}
+
+// Snapshot returns the elements of a TTLSet that have not expired as a Set.
+func (ts *TTLSet[T]) Snapshot() Set[T] {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	now := ts.now()
+	set := New[T]()
+	for s, at := range ts.expires {
+		if !expired(at, now) {
+			set[s] = struct{}{}
+		}
+	}
+	return set
+}
+
+// ToSlice returns an unordered slice of the elements of a TTLSet that have
+// not expired.
+func (ts *TTLSet[T]) ToSlice() []T {
+	return ts.Snapshot().ToSlice()
+}
+
+// EvictExpired evicts all expired elements from a TTLSet and returns how
+// many were evicted.
+func (ts *TTLSet[T]) EvictExpired() int {
+	ts.mu.Lock()
+	now := ts.now()
+	evicted := make([]T, 0)
+	for s, at := range ts.expires {
+		if expired(at, now) {
+			delete(ts.expires, s)
+			evicted = append(evicted, s)
+		}
+	}
+	fn := ts.onEvict
+	ts.mu.Unlock()
+	if fn != nil {
+		for _, s := range evicted {
+			fn(s)
+		}
+	}
+	return len(evicted)
+}
+
+// StartEviction starts a goroutine calling EvictExpired every interval, until
+// StopEviction is called. Calling it again replaces the running goroutine. It
+// panics if interval is not positive, leaving any running goroutine in place.
+func (ts *TTLSet[T]) StartEviction(interval time.Duration) {
+	if interval <= 0 {
+		panic("set: non-positive interval for StartEviction")
+	}
+	stop := make(chan struct{})
+	ts.mu.Lock()
+	if ts.stop != nil {
+		close(ts.stop)
+	}
+	ts.stop = stop
+	ts.mu.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				ts.EvictExpired()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// StopEviction stops the goroutine started by StartEviction, if any.
+func (ts *TTLSet[T]) StopEviction() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.stop != nil {
+		close(ts.stop)
+		ts.stop = nil
+	}
+}
+
+// evictIfExpired deletes an element if it has expired and reports whether
+// it did. The lock must be held.
+func (ts *TTLSet[T]) evictIfExpired(s T, now time.Time) bool {
+	at, ok := ts.expires[s]
+	if !ok || !expired(at, now) {
+		return false
+	}
+	delete(ts.expires, s)
+	return true
+}
+
+func expired(at, now time.Time) bool {
+	return !at.IsZero() && !now.Before(at)
+}
@@ -0,0 +1,223 @@
+/*
+Open Source Initiative OSI - The MIT License (MIT):Licensing
+
+The MIT License (MIT)
+Copyright (c) 2024 Felix Enescu
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of
+this software and associated documentation files (the "Software"), to deal in
+the Software without restriction, including without limitation the rights to
+use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies
+of the Software, and to permit persons to whom the Software is furnished to do
+so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+*/
+
+package set
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock, safe for concurrent use.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestTTLSetExpiry(t *testing.T) {
+	clock := newFakeClock()
+	ts := NewTTLWithClock[string](time.Minute, clock.Now)
+	ts.Add("a")
+	ts.AddWithTTL("b", 3*time.Minute)
+	ts.AddWithTTL("forever", 0)
+	require.Equal(t, 3, ts.Len())
+	require.True(t, ts.Contains("a"))
+
+	at, ok := ts.ExpiresAt("a")
+	require.True(t, ok)
+	require.Equal(t, clock.Now().Add(time.Minute), at)
+	at, ok = ts.ExpiresAt("forever")
+	require.True(t, ok)
+	require.True(t, at.IsZero())
+	_, ok = ts.ExpiresAt("x")
+	require.False(t, ok)
+
+	clock.Advance(time.Minute)
+	require.False(t, ts.Contains("a"))
+	_, ok = ts.ExpiresAt("a")
+	require.False(t, ok)
+	require.True(t, ts.Contains("b"))
+	require.Equal(t, 2, ts.Len())
+
+	clock.Advance(time.Hour)
+	require.Equal(t, []string{"forever"}, ts.ToSlice())
+	require.Equal(t, NewFromSlice([]string{"forever"}), ts.Snapshot())
+
+	ts.Remove("forever")
+	require.Equal(t, 0, ts.Len())
+}
+
+func TestTTLSetAddResetsExpiry(t *testing.T) {
+	clock := newFakeClock()
+	ts := NewTTLWithClock[int](time.Minute, clock.Now)
+	ts.Add(1)
+	clock.Advance(50 * time.Second)
+	ts.Add(1)
+	clock.Advance(50 * time.Second)
+	require.True(t, ts.Contains(1))
+	clock.Advance(10 * time.Second)
+	require.False(t, ts.Contains(1))
+}
+
+func TestTTLSetEviction(t *testing.T) {
+	clock := newFakeClock()
+	ts := NewTTLWithClock[int](time.Second, clock.Now)
+	var mu sync.Mutex
+	evicted := make([]int, 0)
+	ts.OnEvict(func(s int) {
+		mu.Lock()
+		defer mu.Unlock()
+		evicted = append(evicted, s)
+	})
+	evictedSorted := func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Sorted(slices.Values(evicted))
+	}
+
+	ts.AddWithTTL(1, time.Second)
+	ts.AddWithTTL(2, time.Second)
+	ts.AddWithTTL(3, 2*time.Second)
+	ts.AddWithTTL(4, 3*time.Second)
+	ts.AddWithTTL(5, 0)
+	ts.Remove(2)
+	clock.Advance(time.Second)
+
+	// lazy eviction on access
+	require.False(t, ts.Contains(1))
+	require.False(t, ts.Contains(1))
+	require.Equal(t, []int{1}, evictedSorted())
+
+	clock.Advance(time.Second)
+	ts.Add(3)
+	require.Equal(t, []int{1, 3}, evictedSorted())
+	require.True(t, ts.Contains(3))
+
+	clock.Advance(time.Second)
+	require.Equal(t, 2, ts.EvictExpired())
+	require.Equal(t, 0, ts.EvictExpired())
+	require.Equal(t, []int{1, 3, 3, 4}, evictedSorted())
+	require.Equal(t, []int{5}, ts.ToSlice())
+
+	// periodic eviction
+	ts.Add(6)
+	ts.StartEviction(time.Millisecond)
+	defer ts.StopEviction()
+	clock.Advance(time.Second)
+	require.Eventually(t, func() bool {
+		return len(evictedSorted()) == 5
+	}, 5*time.Second, time.Millisecond)
+	require.Equal(t, []int{1, 3, 3, 4, 6}, evictedSorted())
+	ts.StopEviction()
+	ts.StopEviction()
+}
+
+func TestTTLSetStartEvictionInvalidInterval(t *testing.T) {
+	clock := newFakeClock()
+	ts := NewTTLWithClock[int](time.Second, clock.Now)
+	evicted := make(chan int, 1)
+	ts.OnEvict(func(s int) { evicted <- s })
+	ts.StartEviction(time.Millisecond)
+	defer ts.StopEviction()
+
+	require.Panics(t, func() { ts.StartEviction(0) })
+	require.Panics(t, func() { ts.StartEviction(-time.Second) })
+
+	// the running goroutine keeps evicting
+	ts.Add(1)
+	clock.Advance(time.Second)
+	select {
+	case s := <-evicted:
+		require.Equal(t, 1, s)
+	case <-time.After(5 * time.Second):
+		t.Fatal("eviction stopped after an invalid StartEviction")
+	}
+}
+
+func TestTTLSetCallbackReentrant(t *testing.T) {
+	clock := newFakeClock()
+	ts := NewTTLWithClock[string](time.Second, clock.Now)
+	ts.OnEvict(func(s string) {
+		ts.AddWithTTL("re-"+s, 0)
+	})
+	ts.Add("a")
+	clock.Advance(time.Second)
+	require.Equal(t, 1, ts.EvictExpired())
+	require.True(t, ts.Contains("re-a"))
+}
+
+func TestTTLSetDefaultClock(t *testing.T) {
+	ts := NewTTL[int](time.Hour)
+	ts.Add(1)
+	require.True(t, ts.Contains(1))
+	at, _ := ts.ExpiresAt(1)
+	require.WithinDuration(t, time.Now().Add(time.Hour), at, time.Minute)
+}
+
+func TestTTLSetConcurrentStartEviction(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for round := 0; round < 50; round++ {
+		ts := NewTTL[int](time.Minute)
+		var wg sync.WaitGroup
+		for i := 0; i < 32; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ts.StartEviction(time.Millisecond)
+			}()
+		}
+		wg.Wait()
+		ts.StopEviction()
+	}
+
+	// every eviction goroutine must have been stopped; polled by hand, as
+	// require.Eventually runs its own goroutine
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	require.LessOrEqual(t, runtime.NumGoroutine(), before)
+}